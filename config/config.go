@@ -4,15 +4,29 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Port     string
+	Database      DatabaseConfig
+	Port          string
+	ReactionTypes []string
+	Timeline      TimelineConfig
+	Ranking       RankingConfig
+	Trending      TrendingConfig
+	Media         MediaConfig
+	Scheduler     SchedulerConfig
+	Auth          AuthConfig
+
+	UsernameCooldownDays int
+	AdminUserIDs         []string
 }
 
+var AppConfig *Config
+
 type DatabaseConfig struct {
 	Host     string
 	Port     string
@@ -22,6 +36,43 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+type TimelineConfig struct {
+	MaxLength     int
+	FanoutLimit   int
+	MaxAgeSeconds int
+}
+
+type MediaConfig struct {
+	Dir            string
+	MaxUploadBytes int64
+	ThumbnailSize  int
+}
+
+type TrendingConfig struct {
+	WindowHours    int
+	RefreshMinutes int
+	Limit          int
+}
+
+type AuthConfig struct {
+	TokenSecret   string
+	TokenTTLHours int
+}
+
+type SchedulerConfig struct {
+	IntervalSeconds int
+	BatchSize       int
+}
+
+type RankingConfig struct {
+	CandidateLimit int
+	HalfLifeHours  float64
+	RecencyWeight  float64
+	LikeWeight     float64
+	CommentWeight  float64
+	AffinityWeight float64
+}
+
 func LoadConfig() *Config {
 	err := godotenv.Load()
 	if err != nil {
@@ -37,13 +88,48 @@ func LoadConfig() *Config {
 			Name:     getEnv("DB_NAME", "social_media"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
-		Port: getEnv("PORT", "8080"),
+		Port:          getEnv("PORT", "8080"),
+		ReactionTypes: getEnvList("REACTION_TYPES", "like,love,haha,wow,sad,angry"),
+		Timeline: TimelineConfig{
+			MaxLength:     getEnvInt("TIMELINE_MAX_LENGTH", 800),
+			FanoutLimit:   getEnvInt("TIMELINE_FANOUT_LIMIT", 10000),
+			MaxAgeSeconds: getEnvInt("TIMELINE_MAX_AGE_SECONDS", 300),
+		},
+		Ranking: RankingConfig{
+			CandidateLimit: getEnvInt("RANKING_CANDIDATE_LIMIT", 500),
+			HalfLifeHours:  getEnvFloat("RANKING_HALF_LIFE_HOURS", 12),
+			RecencyWeight:  getEnvFloat("RANKING_RECENCY_WEIGHT", 3),
+			LikeWeight:     getEnvFloat("RANKING_LIKE_WEIGHT", 1),
+			CommentWeight:  getEnvFloat("RANKING_COMMENT_WEIGHT", 1.5),
+			AffinityWeight: getEnvFloat("RANKING_AFFINITY_WEIGHT", 2),
+		},
+		Trending: TrendingConfig{
+			WindowHours:    getEnvInt("TRENDING_WINDOW_HOURS", 24),
+			RefreshMinutes: getEnvInt("TRENDING_REFRESH_MINUTES", 5),
+			Limit:          getEnvInt("TRENDING_LIMIT", 50),
+		},
+		Media: MediaConfig{
+			Dir:            getEnv("MEDIA_DIR", "uploads"),
+			MaxUploadBytes: int64(getEnvInt("MEDIA_MAX_UPLOAD_MB", 10)) << 20,
+			ThumbnailSize:  getEnvInt("MEDIA_THUMBNAIL_SIZE", 320),
+		},
+		Scheduler: SchedulerConfig{
+			IntervalSeconds: getEnvInt("SCHEDULER_INTERVAL_SECONDS", 30),
+			BatchSize:       getEnvInt("SCHEDULER_BATCH_SIZE", 100),
+		},
+		Auth: AuthConfig{
+			TokenSecret:   getEnv("AUTH_TOKEN_SECRET", ""),
+			TokenTTLHours: getEnvInt("AUTH_TOKEN_TTL_HOURS", 720),
+		},
+		UsernameCooldownDays: getEnvInt("USERNAME_COOLDOWN_DAYS", 30),
+		AdminUserIDs:         getEnvList("ADMIN_USER_IDS", ""),
 	}
 
 	log.Printf("Configuration loaded - Port: %s, DB: %s@%s:%s/%s",
 		config.Port, config.Database.User, config.Database.Host,
 		config.Database.Port, config.Database.Name)
 
+	AppConfig = config
 	return config
 }
 
@@ -64,3 +150,31 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil {
+		log.Printf("Invalid value for %s, using default %d", key, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, strconv.FormatFloat(defaultValue, 'f', -1, 64)), 64)
+	if err != nil {
+		log.Printf("Invalid value for %s, using default %g", key, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var blockService = services.NewBlockService()
+
+func BlockUser(c *gin.Context) {
+	block, err := blockService.BlockUser(c.GetString("verified_viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "cannot block yourself" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "user not found" || err.Error() == "blocker user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "user already blocked" {
+			status = http.StatusConflict
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to block user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Message: "User blocked successfully",
+		Data:    block,
+		Error:   nil,
+	})
+}
+
+func UnblockUser(c *gin.Context) {
+	err := blockService.UnblockUser(c.GetString("verified_viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "block not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to unblock user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "User unblocked successfully",
+		Data:    nil,
+		Error:   nil,
+	})
+}
+
+func GetMyBlocks(c *gin.Context) {
+	blocks, err := blockService.GetBlocks(c.GetString("verified_viewer_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch blocks",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Blocks retrieved successfully",
+		Data:    blocks,
+		Error:   nil,
+	})
+}
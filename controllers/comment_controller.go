@@ -5,6 +5,7 @@ import (
 
 	"social-media-api/models"
 	"social-media-api/services"
+	"social-media-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -57,7 +58,7 @@ func GetCommentsByPostID(c *gin.Context) {
 		return
 	}
 
-	comments, err := commentService.GetCommentsByPostID(postID)
+	comments, err := commentService.GetCommentsByPostID(c.GetString("verified_viewer_id"), postID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "post not found" {
@@ -72,6 +73,15 @@ func GetCommentsByPostID(c *gin.Context) {
 		return
 	}
 
+	if err := commentService.PrepareComments(comments, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch comments",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Comments retrieved successfully",
 		Data:    comments,
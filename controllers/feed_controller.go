@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var feedService = services.NewFeedService()
+
+func GetFeed(c *gin.Context) {
+	respondWithFeed(c, c.GetString("verified_viewer_id"))
+}
+
+func GetUserFeed(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "User ID is required",
+			Data:    nil,
+			Error:   "missing user id",
+		})
+		return
+	}
+
+	if err := userService.CheckUserVisible(c.GetString("verified_viewer_id"), userID); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "this account is private" || err.Error() == "user is blocked" {
+			status = http.StatusForbidden
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch feed",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondWithFeed(c, userID)
+}
+
+func respondWithFeed(c *gin.Context, userID string) {
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var feed *models.Feed
+	switch c.DefaultQuery("mode", "chronological") {
+	case "chronological":
+		feed, err = feedService.GetHomeFeed(c.GetString("verified_viewer_id"), userID, c.Query("cursor"), limit)
+	case "ranked":
+		feed, err = feedService.GetRankedFeed(c.GetString("verified_viewer_id"), userID, limit, offset)
+	default:
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Failed to fetch feed",
+			Data:    nil,
+			Error:   "invalid feed mode",
+		})
+		return
+	}
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "invalid cursor" {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch feed",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Someone else's timeline may include private accounts the viewer
+	// has not been approved to follow.
+	if viewerID := c.GetString("verified_viewer_id"); viewerID != userID {
+		feed.Posts, err = postService.FilterVisiblePosts(viewerID, feed.Posts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.Response{
+				Message: "Failed to fetch feed",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), feed.Posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch feed",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Feed retrieved successfully",
+		Data:    feed,
+		Error:   nil,
+	})
+}
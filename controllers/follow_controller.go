@@ -5,6 +5,7 @@ import (
 
 	"social-media-api/models"
 	"social-media-api/services"
+	"social-media-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,8 +30,10 @@ func CreateFollow(c *gin.Context) {
 			status = http.StatusBadRequest
 		} else if err.Error() == "follower user not found" || err.Error() == "following user not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "already following this user" {
+		} else if err.Error() == "already following this user" || err.Error() == "follow request already sent" {
 			status = http.StatusConflict
+		} else if err.Error() == "cannot follow this user" {
+			status = http.StatusForbidden
 		}
 
 		c.JSON(status, models.Response{
@@ -41,6 +44,15 @@ func CreateFollow(c *gin.Context) {
 		return
 	}
 
+	if follow.Status == "requested" {
+		c.JSON(http.StatusAccepted, models.Response{
+			Message: "Follow request sent",
+			Data:    follow,
+			Error:   nil,
+		})
+		return
+	}
+
 	c.JSON(http.StatusCreated, models.Response{
 		Message: "Follow created successfully",
 		Data:    follow,
@@ -92,11 +104,13 @@ func GetFollowers(c *gin.Context) {
 		return
 	}
 
-	followers, err := followService.GetFollowers(userID)
+	followers, err := followService.GetFollowers(c.GetString("verified_viewer_id"), userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "this account is private" || err.Error() == "user is blocked" {
+			status = http.StatusForbidden
 		}
 
 		c.JSON(status, models.Response{
@@ -107,6 +121,15 @@ func GetFollowers(c *gin.Context) {
 		return
 	}
 
+	if err := followService.ExpandFollows(followers, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch followers",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Followers retrieved successfully",
 		Data:    followers,
@@ -125,11 +148,13 @@ func GetFollowing(c *gin.Context) {
 		return
 	}
 
-	following, err := followService.GetFollowing(userID)
+	following, err := followService.GetFollowing(c.GetString("verified_viewer_id"), userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "this account is private" || err.Error() == "user is blocked" {
+			status = http.StatusForbidden
 		}
 
 		c.JSON(status, models.Response{
@@ -140,9 +165,116 @@ func GetFollowing(c *gin.Context) {
 		return
 	}
 
+	if err := followService.ExpandFollows(following, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch following",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Following retrieved successfully",
 		Data:    following,
 		Error:   nil,
 	})
 }
+
+func GetFollowRequests(c *gin.Context) {
+	userID := c.Param("id")
+	if !requireAccountOwner(c, userID, "Failed to fetch follow requests") {
+		return
+	}
+
+	requests, err := followService.GetFollowRequests(userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch follow requests",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Follow requests retrieved successfully",
+		Data:    requests,
+		Error:   nil,
+	})
+}
+
+func ApproveFollowRequest(c *gin.Context) {
+	userID := c.Param("id")
+	if !requireAccountOwner(c, userID, "Failed to approve follow request") {
+		return
+	}
+
+	follow, err := followService.ApproveFollowRequest(userID, c.Param("requester_id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "follow request not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to approve follow request",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Follow request approved",
+		Data:    follow,
+		Error:   nil,
+	})
+}
+
+func RejectFollowRequest(c *gin.Context) {
+	userID := c.Param("id")
+	if !requireAccountOwner(c, userID, "Failed to reject follow request") {
+		return
+	}
+
+	err := followService.RejectFollowRequest(userID, c.Param("requester_id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "follow request not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to reject follow request",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Follow request rejected",
+		Data:    nil,
+		Error:   nil,
+	})
+}
+
+// requireAccountOwner only lets the account itself through, responding with
+// 403 for anyone else.
+func requireAccountOwner(c *gin.Context, userID, message string) bool {
+	if c.GetString("verified_viewer_id") != userID {
+		c.JSON(http.StatusForbidden, models.Response{
+			Message: message,
+			Data:    nil,
+			Error:   "not allowed to manage this account",
+		})
+		return false
+	}
+	return true
+}
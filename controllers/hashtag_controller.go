@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var hashtagService = services.NewHashtagService()
+
+func SearchHashtags(c *gin.Context) {
+	limit, err := utils.ParseLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	hashtags, err := hashtagService.SearchHashtags(c.Query("prefix"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch hashtags",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Hashtags retrieved successfully",
+		Data:    hashtags,
+		Error:   nil,
+	})
+}
+
+func GetPostsByHashtag(c *gin.Context) {
+	tag := c.Param("tag")
+	if tag == "" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Hashtag is required",
+			Data:    nil,
+			Error:   "missing hashtag",
+		})
+		return
+	}
+
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts, err := hashtagService.GetPostsByHashtag(c.GetString("verified_viewer_id"), tag, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch hashtag posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch hashtag posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Hashtag posts retrieved successfully",
+		Data:    posts,
+		Error:   nil,
+	})
+}
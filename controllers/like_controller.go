@@ -5,6 +5,7 @@ import (
 
 	"social-media-api/models"
 	"social-media-api/services"
+	"social-media-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,7 +28,7 @@ func CreateLike(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" || err.Error() == "post not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "satu user hanya boleh like satu post satu kali" {
+		} else if err.Error() == "already liked this post" || err.Error() == "already reacted to this post" {
 			status = http.StatusConflict
 		}
 
@@ -46,6 +47,72 @@ func CreateLike(c *gin.Context) {
 	})
 }
 
+func SetLike(c *gin.Context) {
+	var req models.LikeState
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid JSON format",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Liked == nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Failed to update like",
+			Data:    nil,
+			Error:   "liked is required",
+		})
+		return
+	}
+
+	err := likeService.SetLike(req.UserID, req.PostID, *req.Liked)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" || err.Error() == "post not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to update like",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Like updated successfully",
+		Data:    req,
+		Error:   nil,
+	})
+}
+
+func DeleteLike(c *gin.Context) {
+	var like models.Like
+	if err := c.ShouldBindJSON(&like); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid JSON format",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := likeService.DeleteLike(like.UserID, like.PostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to delete like",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func GetLikesByPostID(c *gin.Context) {
 	postID := c.Param("id")
 	if postID == "" {
@@ -57,7 +124,7 @@ func GetLikesByPostID(c *gin.Context) {
 		return
 	}
 
-	likes, err := likeService.GetLikesByPostID(postID)
+	likes, err := likeService.GetLikesByPostID(c.GetString("verified_viewer_id"), postID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "post not found" {
@@ -72,6 +139,15 @@ func GetLikesByPostID(c *gin.Context) {
 		return
 	}
 
+	if err := likeService.ExpandLikes(likes, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch post likes",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Post likes retrieved successfully",
 		Data:    likes,
@@ -90,11 +166,13 @@ func GetLikesByUserID(c *gin.Context) {
 		return
 	}
 
-	likes, err := likeService.GetLikesByUserID(userID)
+	likes, err := likeService.GetLikesByUserID(c.GetString("verified_viewer_id"), userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "this account is private" || err.Error() == "user is blocked" {
+			status = http.StatusForbidden
 		}
 
 		c.JSON(status, models.Response{
@@ -105,9 +183,129 @@ func GetLikesByUserID(c *gin.Context) {
 		return
 	}
 
+	if err := likeService.ExpandLikes(likes, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch user likes",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "User likes retrieved successfully",
 		Data:    likes,
 		Error:   nil,
 	})
 }
+
+func SetReaction(c *gin.Context) {
+	var like models.Like
+	if err := c.ShouldBindJSON(&like); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid JSON format",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := likeService.SetReaction(&like)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "reaction is required" || err.Error() == "unsupported reaction" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "user not found" || err.Error() == "post not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to set reaction",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Reaction set successfully",
+		Data:    like,
+		Error:   nil,
+	})
+}
+
+func DeleteReaction(c *gin.Context) {
+	var like models.Like
+	if err := c.ShouldBindJSON(&like); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid JSON format",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	err := likeService.DeleteReaction(like.UserID, like.PostID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to delete reaction",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func GetReactionsByPostID(c *gin.Context) {
+	postID := c.Param("id")
+	if postID == "" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Post ID is required",
+			Data:    nil,
+			Error:   "missing post id",
+		})
+		return
+	}
+
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	summary, err := likeService.GetReactionsByPostID(c.GetString("verified_viewer_id"), postID, limit, offset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "post not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch post reactions",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := likeService.ExpandLikes(summary.Reactors, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch post reactions",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Post reactions retrieved successfully",
+		Data:    summary,
+		Error:   nil,
+	})
+}
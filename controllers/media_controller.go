@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var mediaService = services.NewMediaService()
+
+func UploadMedia(c *gin.Context) {
+	// Leave room for the multipart envelope around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxUploadBytes()+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		status, message := http.StatusBadRequest, "file is required"
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status, message = http.StatusRequestEntityTooLarge, "file is too large"
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to upload media",
+			Data:    nil,
+			Error:   message,
+		})
+		return
+	}
+	if header.Size > services.MaxUploadBytes() {
+		c.JSON(http.StatusRequestEntityTooLarge, models.Response{
+			Message: "Failed to upload media",
+			Data:    nil,
+			Error:   "file is too large",
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Failed to upload media",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	media, err := mediaService.Upload(c.GetString("viewer_id"), file)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "file is too large" {
+			status = http.StatusRequestEntityTooLarge
+		} else if err.Error() == "unsupported media type" {
+			status = http.StatusUnsupportedMediaType
+		} else if err.Error() == "file is required" || err.Error() == "invalid image" || err.Error() == "image dimensions are too large" {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to upload media",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Message: "Media uploaded successfully",
+		Data:    media,
+		Error:   nil,
+	})
+}
+
+func GetMedia(c *gin.Context) {
+	media, err := mediaService.GetMediaByID(c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "media not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch media",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Media retrieved successfully",
+		Data:    media,
+		Error:   nil,
+	})
+}
+
+func GetMediaFile(c *gin.Context) {
+	serveMedia(c, false)
+}
+
+func GetMediaThumbnail(c *gin.Context) {
+	serveMedia(c, true)
+}
+
+func serveMedia(c *gin.Context, thumbnail bool) {
+	file, mimeType, err := mediaService.OpenMedia(c.Param("id"), thumbnail)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "media not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch media",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", mimeType)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, file)
+}
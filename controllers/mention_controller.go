@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var mentionService = services.NewMentionService()
+
+func GetMentionsByUserID(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "User ID is required",
+			Data:    nil,
+			Error:   "missing user id",
+		})
+		return
+	}
+
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts, err := mentionService.GetPostsMentioningUser(c.GetString("verified_viewer_id"), userID, limit, offset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch mentions",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch mentions",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Mentions retrieved successfully",
+		Data:    posts,
+		Error:   nil,
+	})
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+var muteService = services.NewMuteService()
+
+func MuteUser(c *gin.Context) {
+	var mute models.Mute
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&mute); err != nil {
+			c.JSON(http.StatusBadRequest, models.Response{
+				Message: "Invalid JSON format",
+				Data:    nil,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+	mute.UserID = c.GetString("verified_viewer_id")
+	mute.MutedUserID = c.Param("id")
+
+	err := muteService.MuteUser(&mute)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "cannot mute yourself" || err.Error() == "invalid expiration" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to mute user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "User muted successfully",
+		Data:    mute,
+		Error:   nil,
+	})
+}
+
+func UnmuteUser(c *gin.Context) {
+	err := muteService.UnmuteUser(c.GetString("verified_viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "mute not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to unmute user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "User unmuted successfully",
+		Data:    nil,
+		Error:   nil,
+	})
+}
+
+func GetMyMutes(c *gin.Context) {
+	mutes, err := muteService.GetMutes(c.GetString("verified_viewer_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch mutes",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Mutes retrieved successfully",
+		Data:    mutes,
+		Error:   nil,
+	})
+}
+
+func MuteWord(c *gin.Context) {
+	var word models.MutedWord
+	if err := c.ShouldBindJSON(&word); err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid JSON format",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	word.UserID = c.GetString("verified_viewer_id")
+
+	err := muteService.MuteWord(&word)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "word is required", "word is too long", "invalid muted word", "invalid expiration":
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to mute word",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Word muted successfully",
+		Data:    word,
+		Error:   nil,
+	})
+}
+
+func UnmuteWord(c *gin.Context) {
+	err := muteService.UnmuteWord(c.GetString("verified_viewer_id"), c.Param("word_id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "muted word not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to unmute word",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Word unmuted successfully",
+		Data:    nil,
+		Error:   nil,
+	})
+}
+
+func GetMyMutedWords(c *gin.Context) {
+	words, err := muteService.GetMutedWords(c.GetString("verified_viewer_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch muted words",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Muted words retrieved successfully",
+		Data:    words,
+		Error:   nil,
+	})
+}
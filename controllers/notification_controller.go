@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var notificationService = services.NewNotificationService()
+
+func GetMyNotifications(c *gin.Context) {
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	notifications, err := notificationService.GetNotifications(c.GetString("verified_viewer_id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch notifications",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Notifications retrieved successfully",
+		Data:    notifications,
+		Error:   nil,
+	})
+}
+
+func MarkNotificationsRead(c *gin.Context) {
+	if err := notificationService.MarkNotificationsRead(c.GetString("verified_viewer_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to mark notifications as read",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
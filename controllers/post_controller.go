@@ -5,6 +5,7 @@ import (
 
 	"social-media-api/models"
 	"social-media-api/services"
+	"social-media-api/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,8 +28,18 @@ func CreatePost(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if err.Error() == "content tidak boleh kosong" {
 			status = http.StatusBadRequest
-		} else if err.Error() == "user harus valid" {
+		} else if err.Error() == "user harus valid" || err.Error() == "invalid visibility" {
 			status = http.StatusBadRequest
+		} else if err.Error() == "too many attachments" || err.Error() == "duplicate attachment" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "media not found" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "invalid status" || err.Error() == "publish_at is required" || err.Error() == "invalid publish_at" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "quoted post not found" || err.Error() == "parent post not found" {
+			status = http.StatusBadRequest
+		} else if err.Error() == "post cannot be shared" {
+			status = http.StatusForbidden
 		}
 
 		c.JSON(status, models.Response{
@@ -54,15 +65,19 @@ func GetAllPosts(c *gin.Context) {
 	var err error
 
 	if userID != "" || keyword != "" {
-		posts, err = postService.GetPostsWithFilters(userID, keyword)
+		posts, err = postService.GetPostsWithFilters(c.GetString("verified_viewer_id"), userID, keyword)
 	} else {
-		posts, err = postService.GetAllPosts()
+		posts, err = postService.GetAllPosts(c.GetString("verified_viewer_id"))
 	}
 
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "this account is private" || err.Error() == "user is blocked" {
+			status = http.StatusForbidden
+		} else if err.Error() == "invalid search query" {
+			status = http.StatusBadRequest
 		}
 
 		c.JSON(status, models.Response{
@@ -73,6 +88,15 @@ func GetAllPosts(c *gin.Context) {
 		return
 	}
 
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Posts retrieved successfully",
 		Data:    posts,
@@ -91,7 +115,7 @@ func GetPostByID(c *gin.Context) {
 		return
 	}
 
-	post, err := postService.GetPostByID(id)
+	post, err := postService.GetPostByID(c.GetString("verified_viewer_id"), id)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "post not found" {
@@ -106,9 +130,58 @@ func GetPostByID(c *gin.Context) {
 		return
 	}
 
+	posts := []models.Post{*post}
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch post",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Post retrieved successfully",
-		Data:    post,
+		Data:    posts[0],
+		Error:   nil,
+	})
+}
+
+func GetThread(c *gin.Context) {
+	thread, err := postService.GetThread(c.GetString("verified_viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "post not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch thread",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts := make([]models.Post, 0, len(thread.Ancestors)+1+len(thread.Descendants))
+	posts = append(posts, thread.Ancestors...)
+	posts = append(posts, thread.Post)
+	posts = append(posts, thread.Descendants...)
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch thread",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	thread.Ancestors = posts[:len(thread.Ancestors)]
+	thread.Post = posts[len(thread.Ancestors)]
+	thread.Descendants = posts[len(thread.Ancestors)+1:]
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Thread retrieved successfully",
+		Data:    thread,
 		Error:   nil,
 	})
 }
@@ -124,11 +197,13 @@ func GetPostsByUserID(c *gin.Context) {
 		return
 	}
 
-	posts, err := postService.GetPostsByUserID(userID)
+	posts, err := postService.GetPostsByUserID(c.GetString("verified_viewer_id"), userID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "this account is private" || err.Error() == "user is blocked" {
+			status = http.StatusForbidden
 		}
 
 		c.JSON(status, models.Response{
@@ -139,6 +214,15 @@ func GetPostsByUserID(c *gin.Context) {
 		return
 	}
 
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch user posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "User posts retrieved successfully",
 		Data:    posts,
@@ -146,6 +230,95 @@ func GetPostsByUserID(c *gin.Context) {
 	})
 }
 
+func GetMyDrafts(c *gin.Context) {
+	posts, err := postService.GetDrafts(c.GetString("verified_viewer_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch drafts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch drafts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Drafts retrieved successfully",
+		Data:    posts,
+		Error:   nil,
+	})
+}
+
+func GetMyScheduledPosts(c *gin.Context) {
+	posts, err := postService.GetScheduledPosts(c.GetString("verified_viewer_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch scheduled posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch scheduled posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Scheduled posts retrieved successfully",
+		Data:    posts,
+		Error:   nil,
+	})
+}
+
+func PublishPost(c *gin.Context) {
+	post, err := postService.PublishPost(c.GetString("verified_viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "post not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "post already published" {
+			status = http.StatusConflict
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to publish post",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts := []models.Post{*post}
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to publish post",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Post published successfully",
+		Data:    posts[0],
+		Error:   nil,
+	})
+}
+
 func DeletePost(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var repostService = services.NewRepostService()
+
+func Repost(c *gin.Context) {
+	post, err := repostService.Repost(c.GetString("viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "post not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "post cannot be shared" {
+			status = http.StatusForbidden
+		} else if err.Error() == "post already reposted" {
+			status = http.StatusConflict
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to repost",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts := []models.Post{*post}
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to repost",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Response{
+		Message: "Post reposted successfully",
+		Data:    posts[0],
+		Error:   nil,
+	})
+}
+
+func Unrepost(c *gin.Context) {
+	err := repostService.Unrepost(c.GetString("viewer_id"), c.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "repost not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to undo repost",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Repost removed successfully",
+		Data:    nil,
+		Error:   nil,
+	})
+}
+
+func GetReposts(c *gin.Context) {
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts, err := repostService.GetReposts(c.GetString("verified_viewer_id"), c.Param("id"), limit, offset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "post not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch reposts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch reposts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Reposts retrieved successfully",
+		Data:    posts,
+		Error:   nil,
+	})
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var searchService = services.NewSearchService()
+
+func SearchPosts(c *gin.Context) {
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	results, err := searchService.SearchPosts(c.GetString("verified_viewer_id"), c.Query("q"), limit, offset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "invalid search query" {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to search posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts := make([]models.Post, len(results))
+	for i, result := range results {
+		posts[i] = result.Post
+	}
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to search posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	for i := range results {
+		results[i].Post = posts[i]
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Posts retrieved successfully",
+		Data:    results,
+		Error:   nil,
+	})
+}
+
+func SearchUsers(c *gin.Context) {
+	limit, offset, err := utils.ParsePagination(c.Query("limit"), c.Query("offset"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	viewerID := c.GetString("verified_viewer_id")
+	results, err := searchService.SearchUsers(viewerID, c.Query("q"), limit, offset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "invalid search query" {
+			status = http.StatusBadRequest
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to search users",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	users := make([]models.User, len(results))
+	for i, result := range results {
+		users[i] = result.User
+	}
+	if err := userService.PrepareUsers(viewerID, users); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to search users",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	for i := range results {
+		results[i].User = users[i]
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Users retrieved successfully",
+		Data:    results,
+		Error:   nil,
+	})
+}
+
+func AutocompleteUsernames(c *gin.Context) {
+	limit, err := utils.ParseLimit(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	users, err := searchService.AutocompleteUsernames(c.GetString("verified_viewer_id"), c.Query("prefix"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch users",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Users retrieved successfully",
+		Data:    users,
+		Error:   nil,
+	})
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"net/http"
+
+	"social-media-api/models"
+	"social-media-api/services"
+	"social-media-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+var trendingService = services.NewTrendingService()
+
+func GetTrendingPosts(c *gin.Context) {
+	limit, err := utils.ParseLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	trending, err := trendingService.GetTrendingPosts(c.GetString("verified_viewer_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch trending posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	posts := make([]models.Post, len(trending))
+	for i, item := range trending {
+		posts[i] = item.Post
+	}
+	if err := postService.PreparePosts(c.GetString("verified_viewer_id"), posts, utils.ParseIncludes(c.Query("include"))); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch trending posts",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+	for i := range trending {
+		trending[i].Post = posts[i]
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Trending posts retrieved successfully",
+		Data:    trending,
+		Error:   nil,
+	})
+}
+
+func GetTrendingHashtags(c *gin.Context) {
+	limit, err := utils.ParseLimit(c.Query("limit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Invalid pagination parameters",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "Trending hashtags retrieved successfully",
+		Data:    trendingService.GetTrendingHashtags(limit),
+		Error:   nil,
+	})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"net/url"
 
 	"social-media-api/models"
 	"social-media-api/services"
@@ -25,9 +26,9 @@ func CreateUser(c *gin.Context) {
 	err := userService.CreateUser(&user)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "username or email already exists" {
+		if err.Error() == "username or email already exists" || err.Error() == "username is reserved" {
 			status = http.StatusConflict
-		} else if err.Error() == "username is required" || err.Error() == "email is required" || err.Error() == "invalid email format" {
+		} else if isUserValidationError(err) {
 			status = http.StatusBadRequest
 		}
 
@@ -57,6 +58,15 @@ func GetAllUsers(c *gin.Context) {
 		return
 	}
 
+	if err := userService.PrepareUsers(c.GetString("verified_viewer_id"), users); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch users",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "Users retrieved successfully",
 		Data:    users,
@@ -90,9 +100,68 @@ func GetUserByID(c *gin.Context) {
 		return
 	}
 
+	users := []models.User{*user}
+	if err := userService.PrepareUsers(c.GetString("verified_viewer_id"), users); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "User retrieved successfully",
-		Data:    user,
+		Data:    users[0],
+		Error:   nil,
+	})
+}
+
+func GetUserByUsername(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, models.Response{
+			Message: "Username is required",
+			Data:    nil,
+			Error:   "missing username",
+		})
+		return
+	}
+
+	user, err := userService.GetUserByUsername(username)
+	if err != nil && err.Error() == "user not found" {
+		if current, renameErr := userService.GetRenamedUsername(username); renameErr == nil {
+			c.Redirect(http.StatusMovedPermanently, "/users/by-username/"+url.PathEscape(current))
+			return
+		}
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		}
+
+		c.JSON(status, models.Response{
+			Message: "Failed to fetch user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	users := []models.User{*user}
+	if err := userService.PrepareUsers(c.GetString("verified_viewer_id"), users); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to fetch user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		Message: "User retrieved successfully",
+		Data:    users[0],
 		Error:   nil,
 	})
 }
@@ -107,9 +176,12 @@ func UpdateUser(c *gin.Context) {
 		})
 		return
 	}
+	if !requireAccountManager(c, id, "Failed to update user") {
+		return
+	}
 
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
+	var update models.UserUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
 		c.JSON(http.StatusBadRequest, models.Response{
 			Message: "Invalid JSON format",
 			Data:    nil,
@@ -118,14 +190,14 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	err := userService.UpdateUser(id, &user)
+	user, err := userService.UpdateUser(id, &update)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "username or email already exists" {
+		} else if err.Error() == "username or email already exists" || err.Error() == "username is reserved" {
 			status = http.StatusConflict
-		} else if err.Error() == "username is required" || err.Error() == "email is required" || err.Error() == "invalid email format" {
+		} else if isUserValidationError(err) {
 			status = http.StatusBadRequest
 		}
 
@@ -137,9 +209,19 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	users := []models.User{*user}
+	if err := userService.PrepareUsers(c.GetString("verified_viewer_id"), users); err != nil {
+		c.JSON(http.StatusInternalServerError, models.Response{
+			Message: "Failed to update user",
+			Data:    nil,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, models.Response{
 		Message: "User updated successfully",
-		Data:    user,
+		Data:    users[0],
 		Error:   nil,
 	})
 }
@@ -154,6 +236,9 @@ func DeleteUser(c *gin.Context) {
 		})
 		return
 	}
+	if !requireAccountManager(c, id, "Failed to delete user") {
+		return
+	}
 
 	err := userService.DeleteUser(id)
 	if err != nil {
@@ -176,3 +261,24 @@ func DeleteUser(c *gin.Context) {
 		Error:   nil,
 	})
 }
+
+// requireAccountManager lets the account itself or an admin through,
+// responding with 403 for anyone else.
+func requireAccountManager(c *gin.Context, userID, message string) bool {
+	if services.IsAdmin(c.GetString("verified_viewer_id")) {
+		return true
+	}
+	return requireAccountOwner(c, userID, message)
+}
+
+func isUserValidationError(err error) bool {
+	switch err.Error() {
+	case "username is required", "email is required", "invalid email format",
+		"display name is too long", "location is too long", "pronouns is too long",
+		"invalid website url", "invalid avatar url", "invalid banner url",
+		"invalid birthday", "invalid birthday visibility",
+		"too many profile fields", "invalid profile field":
+		return true
+	}
+	return false
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -25,6 +27,8 @@ func InitDB(dbURL string) {
 
 func CreateTables() {
 	query := `
+	CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 	CREATE TABLE IF NOT EXISTS users (
 		id VARCHAR(36) PRIMARY KEY,
 		username VARCHAR(50) UNIQUE NOT NULL,
@@ -68,15 +72,244 @@ func CreateTables() {
 		FOREIGN KEY (following_id) REFERENCES users(id) ON DELETE CASCADE,
 		UNIQUE(follower_id, following_id),
 		CHECK (follower_id != following_id)
-	);`
+	);
+
+	CREATE TABLE IF NOT EXISTS hashtags (
+		id VARCHAR(36) PRIMARY KEY,
+		tag VARCHAR(100) UNIQUE NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS post_hashtags (
+		post_id VARCHAR(36) NOT NULL,
+		hashtag_id VARCHAR(36) NOT NULL,
+		FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+		FOREIGN KEY (hashtag_id) REFERENCES hashtags(id) ON DELETE CASCADE,
+		PRIMARY KEY (post_id, hashtag_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS media (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		mime_type VARCHAR(50) NOT NULL,
+		size BIGINT NOT NULL,
+		width INTEGER NOT NULL,
+		height INTEGER NOT NULL,
+		storage_key VARCHAR(200) NOT NULL,
+		thumbnail_key VARCHAR(200) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS post_attachments (
+		post_id VARCHAR(36) NOT NULL,
+		media_id VARCHAR(36) NOT NULL,
+		position INTEGER NOT NULL,
+		FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+		FOREIGN KEY (media_id) REFERENCES media(id) ON DELETE CASCADE,
+		PRIMARY KEY (post_id, media_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS username_history (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		username VARCHAR(50) NOT NULL,
+		released_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS mentions (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		post_id VARCHAR(36),
+		comment_id VARCHAR(36),
+		start_offset INTEGER NOT NULL,
+		length INTEGER NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+		FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+		CHECK ((post_id IS NULL) != (comment_id IS NULL))
+	);
+
+	CREATE TABLE IF NOT EXISTS follow_requests (
+		id VARCHAR(36) PRIMARY KEY,
+		requester_id VARCHAR(36) NOT NULL,
+		target_id VARCHAR(36) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (target_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(requester_id, target_id),
+		CHECK (requester_id != target_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS blocks (
+		id VARCHAR(36) PRIMARY KEY,
+		blocker_id VARCHAR(36) NOT NULL,
+		blocked_id VARCHAR(36) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (blocker_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (blocked_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(blocker_id, blocked_id),
+		CHECK (blocker_id != blocked_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS mutes (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		muted_user_id VARCHAR(36) NOT NULL,
+		expires_at TIMESTAMPTZ,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (muted_user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(user_id, muted_user_id),
+		CHECK (user_id != muted_user_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS notifications (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		actor_id VARCHAR(36) NOT NULL,
+		type VARCHAR(20) NOT NULL,
+		post_id VARCHAR(36) NOT NULL,
+		comment_id VARCHAR(36),
+		read_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+		FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS muted_words (
+		id VARCHAR(36) PRIMARY KEY,
+		user_id VARCHAR(36) NOT NULL,
+		word VARCHAR(100) NOT NULL,
+		expires_at TIMESTAMPTZ,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(user_id, word)
+	);
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(50) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(500) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS banner_url VARCHAR(500) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS location VARCHAR(100) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS website VARCHAR(500) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS birthday DATE;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS birthday_visibility VARCHAR(20) NOT NULL DEFAULT 'private';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS pronouns VARCHAR(40) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_fields JSONB NOT NULL DEFAULT '[]';
+	DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'joined_at') THEN
+			ALTER TABLE users ADD COLUMN joined_at TIMESTAMP;
+			-- users has no creation time of its own, so existing accounts get their earliest
+			-- activity; accounts without any stay NULL rather than the migration time.
+			UPDATE users u SET joined_at = (SELECT MIN(first_seen) FROM (
+				SELECT MIN(created_at) AS first_seen FROM posts WHERE user_id = u.id
+				UNION ALL SELECT MIN(created_at) FROM comments WHERE user_id = u.id
+				UNION ALL SELECT MIN(created_at) FROM follows WHERE follower_id = u.id OR following_id = u.id
+			) activity);
+			ALTER TABLE users ALTER COLUMN joined_at SET DEFAULT CURRENT_TIMESTAMP;
+		END IF;
+	END $$;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'public';
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS repost_of_id VARCHAR(36) REFERENCES posts(id) ON DELETE CASCADE;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS quote_of_id VARCHAR(36) REFERENCES posts(id) ON DELETE SET NULL;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS is_quote BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS in_reply_to_id VARCHAR(36) REFERENCES posts(id) ON DELETE SET NULL;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS conversation_id VARCHAR(36);
+	UPDATE posts SET conversation_id = id WHERE conversation_id IS NULL;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+	ALTER TABLE likes ADD COLUMN IF NOT EXISTS reaction VARCHAR(20) NOT NULL DEFAULT 'like';
+	DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'likes' AND column_name = 'created_at') THEN
+			ALTER TABLE likes ADD COLUMN created_at TIMESTAMP;
+			-- The real like time was never recorded; a like cannot predate its post, so the
+			-- post's creation time keeps old likes out of recent windows.
+			UPDATE likes l SET created_at = p.created_at FROM posts p WHERE p.id = l.post_id;
+			ALTER TABLE likes ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+		END IF;
+	END $$;
+
+	CREATE INDEX IF NOT EXISTS idx_posts_user_created ON posts (user_id, created_at DESC, id DESC);
+	CREATE INDEX IF NOT EXISTS idx_follows_following ON follows (following_id);
+	CREATE INDEX IF NOT EXISTS idx_posts_created ON posts (created_at);
+	CREATE INDEX IF NOT EXISTS idx_likes_created ON likes (created_at);
+	CREATE INDEX IF NOT EXISTS idx_comments_created ON comments (created_at);
+	CREATE INDEX IF NOT EXISTS idx_hashtags_tag_prefix ON hashtags (tag text_pattern_ops);
+	CREATE INDEX IF NOT EXISTS idx_post_hashtags_hashtag ON post_hashtags (hashtag_id);
+	CREATE INDEX IF NOT EXISTS idx_mentions_user ON mentions (user_id);
+	CREATE INDEX IF NOT EXISTS idx_mentions_post ON mentions (post_id);
+	CREATE INDEX IF NOT EXISTS idx_mentions_comment ON mentions (comment_id);
+	CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC, id DESC);
+	CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN (search_vector);
+	CREATE INDEX IF NOT EXISTS idx_users_username_prefix ON users (LOWER(username) text_pattern_ops);
+	CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (username gin_trgm_ops);
+	CREATE INDEX IF NOT EXISTS idx_users_bio_trgm ON users USING GIN (bio gin_trgm_ops);
+	CREATE INDEX IF NOT EXISTS idx_username_history_username ON username_history (LOWER(username), released_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_follow_requests_target ON follow_requests (target_id, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_blocks_blocked ON blocks (blocked_id);
+	CREATE INDEX IF NOT EXISTS idx_posts_user_status ON posts (user_id, status) WHERE status <> 'published';
+	CREATE INDEX IF NOT EXISTS idx_posts_publish_at ON posts (publish_at) WHERE status = 'scheduled';
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_user_repost ON posts (user_id, repost_of_id) WHERE repost_of_id IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_posts_repost_of ON posts (repost_of_id) WHERE repost_of_id IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_posts_quote_of ON posts (quote_of_id) WHERE quote_of_id IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_posts_in_reply_to ON posts (in_reply_to_id) WHERE in_reply_to_id IS NOT NULL;
+	CREATE INDEX IF NOT EXISTS idx_posts_conversation ON posts (conversation_id, created_at);`
 
 	_, err := DB.Exec(query)
 	if err != nil {
 		log.Fatal("Failed to create tables:", err)
 	}
+
+	createUsernameIndex()
+	createCounters()
 	log.Println("Tables created successfully")
 }
 
+// createUsernameIndex makes usernames unique regardless of case. Databases
+// from before that rule may hold usernames that differ only by case, which
+// would make the index fail, so those are reported first with what to do.
+func createUsernameIndex() {
+	rows, err := DB.Query(`SELECT LOWER(username), STRING_AGG(username || ' (' || id || ')', ', ' ORDER BY id)
+		FROM users GROUP BY LOWER(username) HAVING COUNT(*) > 1 ORDER BY LOWER(username)`)
+	if err != nil {
+		log.Fatal("Failed to check usernames:", err)
+	}
+	defer rows.Close()
+
+	var conflicts []string
+	for rows.Next() {
+		var username, accounts string
+		if err := rows.Scan(&username, &accounts); err != nil {
+			log.Fatal("Failed to check usernames:", err)
+		}
+		conflicts = append(conflicts, fmt.Sprintf("  %s: %s", username, accounts))
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatal("Failed to check usernames:", err)
+	}
+	if len(conflicts) > 0 {
+		log.Fatalf("Usernames must be unique regardless of case, but these accounts share a username:\n%s\n"+
+			"Rename all but one account in each group (UPDATE users SET username = ... WHERE id = ...) and restart.",
+			strings.Join(conflicts, "\n"))
+	}
+
+	_, err = DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username))`)
+	if err != nil {
+		log.Fatal("Failed to create username index:", err)
+	}
+}
+
 func CloseDB() {
 	if DB != nil {
 		DB.Close()
@@ -0,0 +1,132 @@
+package database
+
+import "log"
+
+const counterQuery = `
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS like_count INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_count INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS post_count INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS follower_count INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS following_count INTEGER NOT NULL DEFAULT 0;
+
+	CREATE OR REPLACE FUNCTION update_like_count() RETURNS TRIGGER AS $$
+	BEGIN
+		IF TG_OP IN ('INSERT', 'UPDATE') THEN
+			IF NEW.reaction = 'like' THEN
+				UPDATE posts SET like_count = like_count + 1 WHERE id = NEW.post_id;
+			END IF;
+		END IF;
+		IF TG_OP IN ('DELETE', 'UPDATE') THEN
+			IF OLD.reaction = 'like' THEN
+				UPDATE posts SET like_count = like_count - 1 WHERE id = OLD.post_id;
+			END IF;
+		END IF;
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+
+	CREATE OR REPLACE FUNCTION update_comment_count() RETURNS TRIGGER AS $$
+	BEGIN
+		IF TG_OP = 'INSERT' THEN
+			UPDATE posts SET comment_count = comment_count + 1 WHERE id = NEW.post_id;
+		ELSE
+			UPDATE posts SET comment_count = comment_count - 1 WHERE id = OLD.post_id;
+		END IF;
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+
+	CREATE OR REPLACE FUNCTION update_post_count() RETURNS TRIGGER AS $$
+	BEGIN
+		IF TG_OP IN ('INSERT', 'UPDATE') THEN
+			IF NEW.status = 'published' THEN
+				UPDATE users SET post_count = post_count + 1 WHERE id = NEW.user_id;
+			END IF;
+		END IF;
+		IF TG_OP IN ('DELETE', 'UPDATE') THEN
+			IF OLD.status = 'published' THEN
+				UPDATE users SET post_count = post_count - 1 WHERE id = OLD.user_id;
+			END IF;
+		END IF;
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+
+	CREATE OR REPLACE FUNCTION update_follow_count() RETURNS TRIGGER AS $$
+	BEGIN
+		IF TG_OP = 'INSERT' THEN
+			UPDATE users SET following_count = following_count + 1 WHERE id = NEW.follower_id;
+			UPDATE users SET follower_count = follower_count + 1 WHERE id = NEW.following_id;
+		ELSE
+			UPDATE users SET following_count = following_count - 1 WHERE id = OLD.follower_id;
+			UPDATE users SET follower_count = follower_count - 1 WHERE id = OLD.following_id;
+		END IF;
+		RETURN NULL;
+	END;
+	$$ LANGUAGE plpgsql;
+
+	DROP TRIGGER IF EXISTS likes_count_trigger ON likes;
+	CREATE TRIGGER likes_count_trigger AFTER INSERT OR UPDATE OF reaction OR DELETE ON likes
+		FOR EACH ROW EXECUTE FUNCTION update_like_count();
+
+	DROP TRIGGER IF EXISTS comments_count_trigger ON comments;
+	CREATE TRIGGER comments_count_trigger AFTER INSERT OR DELETE ON comments
+		FOR EACH ROW EXECUTE FUNCTION update_comment_count();
+
+	DROP TRIGGER IF EXISTS posts_count_trigger ON posts;
+	CREATE TRIGGER posts_count_trigger AFTER INSERT OR UPDATE OF status OR DELETE ON posts
+		FOR EACH ROW EXECUTE FUNCTION update_post_count();
+
+	DROP TRIGGER IF EXISTS follows_count_trigger ON follows;
+	CREATE TRIGGER follows_count_trigger AFTER INSERT OR DELETE ON follows
+		FOR EACH ROW EXECUTE FUNCTION update_follow_count();`
+
+const recomputeCountersQuery = `
+	UPDATE posts p SET
+		like_count = (SELECT COUNT(*) FROM likes l WHERE l.post_id = p.id AND l.reaction = 'like'),
+		comment_count = (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id);
+
+	UPDATE users u SET
+		post_count = (SELECT COUNT(*) FROM posts p WHERE p.user_id = u.id AND p.status = 'published'),
+		follower_count = (SELECT COUNT(*) FROM follows f WHERE f.following_id = u.id),
+		following_count = (SELECT COUNT(*) FROM follows f WHERE f.follower_id = u.id);`
+
+// countersExistQuery reports whether every counter column is already there.
+// When any is missing, the columns are about to be added with a default of
+// 0 and the counters are backfilled from the existing rows.
+const countersExistQuery = `SELECT COUNT(*) = 5 FROM information_schema.columns
+	WHERE table_schema = current_schema() AND (
+		(table_name = 'posts' AND column_name IN ('like_count', 'comment_count'))
+		OR (table_name = 'users' AND column_name IN ('post_count', 'follower_count', 'following_count')))`
+
+func createCounters() {
+	var existed bool
+	if err := DB.QueryRow(countersExistQuery).Scan(&existed); err != nil {
+		log.Fatal("Failed to create counters:", err)
+	}
+
+	_, err := DB.Exec(counterQuery)
+	if err != nil {
+		log.Fatal("Failed to create counters:", err)
+	}
+
+	if !existed {
+		if err := RecomputeCounters(); err != nil {
+			log.Fatal("Failed to backfill counters:", err)
+		}
+	}
+}
+
+func RecomputeCounters() error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(recomputeCountersQuery); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
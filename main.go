@@ -1,17 +1,25 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 
 	"social-media-api/config"
 	"social-media-api/database"
 	"social-media-api/middleware"
 	"social-media-api/routes"
+	"social-media-api/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	repairCounters := flag.Bool("repair-counters", false, "recompute post and user counters, then exit")
+	reindexHashtags := flag.Bool("reindex-hashtags", false, "rebuild the hashtag index from post content, then exit")
+	issueToken := flag.String("issue-token", "", "print a bearer token for the given user ID, then exit")
+	flag.Parse()
+
 	cfg := config.LoadConfig()
 
 	database.InitDB(cfg.GetDatabaseURL())
@@ -19,11 +27,40 @@ func main() {
 
 	database.CreateTables()
 
+	if *repairCounters {
+		if err := database.RecomputeCounters(); err != nil {
+			log.Fatal("Failed to recompute counters:", err)
+		}
+		log.Println("Counters recomputed successfully")
+		return
+	}
+
+	if *reindexHashtags {
+		if err := services.ReindexHashtags(); err != nil {
+			log.Fatal("Failed to reindex hashtags:", err)
+		}
+		log.Println("Hashtags reindexed successfully")
+		return
+	}
+
+	if *issueToken != "" {
+		token, err := services.IssueToken(*issueToken)
+		if err != nil {
+			log.Fatal("Failed to issue token:", err)
+		}
+		fmt.Println(token)
+		return
+	}
+
+	services.StartTrendingWorker()
+	services.StartPostScheduler()
+
 	r := gin.Default()
 
 	r.Use(middleware.CORS())
 	r.Use(middleware.Logger())
 	r.Use(middleware.Timestamping())
+	r.Use(middleware.Authenticate())
 
 	routes.SetupRoutes(r)
 
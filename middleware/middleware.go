@@ -2,8 +2,14 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"social-media-api/models"
+	"social-media-api/services"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,7 +17,7 @@ func CORS() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-User-ID, accept, origin, Cache-Control, X-Requested-With")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -41,3 +47,109 @@ func Timestamping() gin.HandlerFunc {
 		c.Next()
 	})
 }
+
+// Authenticate identifies the viewer. A bearer token signed by the server
+// sets both viewer_id and verified_viewer_id. The X-User-ID header only sets
+// viewer_id; visibility checks, private data and account management read
+// verified_viewer_id, so a request with just the header is anonymous to them.
+func Authenticate() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			viewerID, err := services.VerifyToken(strings.TrimSpace(strings.TrimPrefix(header, "Bearer ")))
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, models.Response{
+					Message: "Authentication failed",
+					Data:    nil,
+					Error:   err.Error(),
+				})
+				return
+			}
+			c.Set("viewer_id", viewerID)
+			c.Set("verified_viewer_id", viewerID)
+		} else if viewerID := strings.TrimSpace(c.GetHeader("X-User-ID")); viewerID != "" {
+			c.Set("viewer_id", viewerID)
+		}
+		c.Next()
+	})
+}
+
+func RequireAuth() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if c.GetString("viewer_id") == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.Response{
+				Message: "Authentication required",
+				Data:    nil,
+				Error:   "missing X-User-ID header",
+			})
+			return
+		}
+		c.Next()
+	})
+}
+
+func RequireVerifiedAuth() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if c.GetString("verified_viewer_id") == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.Response{
+				Message: "Authentication required",
+				Data:    nil,
+				Error:   "missing bearer token",
+			})
+			return
+		}
+		c.Next()
+	})
+}
+
+// ResolveUsername lets routes with an :id parameter accept "@username" in
+// place of the user ID by rewriting the parameter before the handler runs.
+func ResolveUsername() gin.HandlerFunc {
+	userService := services.NewUserService()
+
+	return gin.HandlerFunc(func(c *gin.Context) {
+		for i, param := range c.Params {
+			if param.Key != "id" || !strings.HasPrefix(param.Value, "@") {
+				continue
+			}
+
+			user, err := userService.GetUserByUsername(param.Value)
+			if err != nil && err.Error() == "user not found" {
+				if current, renameErr := userService.GetRenamedUsername(param.Value); renameErr == nil {
+					redirectToUsername(c, param.Value, current)
+					return
+				}
+			}
+			if err != nil {
+				status := http.StatusInternalServerError
+				if err.Error() == "user not found" {
+					status = http.StatusNotFound
+				}
+
+				c.AbortWithStatusJSON(status, models.Response{
+					Message: "Failed to resolve username",
+					Data:    nil,
+					Error:   err.Error(),
+				})
+				return
+			}
+
+			c.Params[i].Value = user.ID
+		}
+		c.Next()
+	})
+}
+
+func redirectToUsername(c *gin.Context, oldRef, current string) {
+	status := http.StatusPermanentRedirect
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+
+	location := strings.Replace(c.Request.URL.Path, "/"+oldRef, "/@"+url.PathEscape(current), 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	c.Redirect(status, location)
+	c.Abort()
+}
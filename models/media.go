@@ -0,0 +1,13 @@
+package models
+
+type Media struct {
+	ID           string `json:"id" db:"id"`
+	UserID       string `json:"user_id" db:"user_id"`
+	MimeType     string `json:"mime_type" db:"mime_type"`
+	Size         int64  `json:"size" db:"size"`
+	Width        int    `json:"width" db:"width"`
+	Height       int    `json:"height" db:"height"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	CreatedAt    string `json:"created_at" db:"created_at"`
+}
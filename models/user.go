@@ -1,38 +1,221 @@
 package models
 
 type User struct {
-	ID       string `json:"id" db:"id"`
-	Username string `json:"username" db:"username"`
-	Email    string `json:"email" db:"email"`
-	Bio      string `json:"bio" db:"bio"`
+	ID                 string        `json:"id" db:"id"`
+	Username           string        `json:"username" db:"username"`
+	Email              string        `json:"email,omitempty" db:"email"`
+	Bio                string        `json:"bio" db:"bio"`
+	DisplayName        string        `json:"display_name" db:"display_name"`
+	AvatarURL          string        `json:"avatar_url" db:"avatar_url"`
+	BannerURL          string        `json:"banner_url" db:"banner_url"`
+	Location           string        `json:"location" db:"location"`
+	Website            string        `json:"website" db:"website"`
+	Birthday           string        `json:"birthday,omitempty" db:"birthday"`
+	BirthdayVisibility string        `json:"birthday_visibility,omitempty" db:"birthday_visibility"`
+	Pronouns           string        `json:"pronouns" db:"pronouns"`
+	ProfileFields      ProfileFields `json:"profile_fields" db:"profile_fields"`
+	IsPrivate          bool          `json:"is_private" db:"is_private"`
+	JoinedAt           *string       `json:"joined_at,omitempty" db:"joined_at"`
+	PostCount          int           `json:"post_count" db:"post_count"`
+	FollowerCount      int           `json:"follower_count" db:"follower_count"`
+	FollowingCount     int           `json:"following_count" db:"following_count"`
+	FollowedByMe       *bool         `json:"followed_by_me,omitempty"`
+	FollowsMe          *bool         `json:"follows_me,omitempty"`
+
+	viewer userViewer
+}
+
+// UserUpdate is the body of PUT /users/:id. Fields left out of the request
+// keep their stored value.
+type UserUpdate struct {
+	Username           *string        `json:"username"`
+	Email              *string        `json:"email"`
+	Bio                *string        `json:"bio"`
+	DisplayName        *string        `json:"display_name"`
+	AvatarURL          *string        `json:"avatar_url"`
+	BannerURL          *string        `json:"banner_url"`
+	Location           *string        `json:"location"`
+	Website            *string        `json:"website"`
+	Birthday           *string        `json:"birthday"`
+	BirthdayVisibility *string        `json:"birthday_visibility"`
+	Pronouns           *string        `json:"pronouns"`
+	ProfileFields      *ProfileFields `json:"profile_fields"`
+	IsPrivate          *bool          `json:"is_private"`
 }
 
 type Post struct {
-	ID        string `json:"id" db:"id"`
-	UserID    string `json:"user_id" db:"user_id"`
-	Content   string `json:"content" db:"content"`
-	CreatedAt string `json:"created_at" db:"created_at"`
+	ID             string       `json:"id" db:"id"`
+	UserID         string       `json:"user_id" db:"user_id"`
+	Content        string       `json:"content" db:"content"`
+	Visibility     string       `json:"visibility" db:"visibility"`
+	Status         string       `json:"status" db:"status"`
+	PublishAt      *string      `json:"publish_at,omitempty" db:"publish_at"`
+	RepostOfID     *string      `json:"repost_of_id,omitempty" db:"repost_of_id"`
+	QuoteOfID      *string      `json:"quote_of_id,omitempty" db:"quote_of_id"`
+	IsQuote        bool         `json:"-" db:"is_quote"`
+	InReplyToID    *string      `json:"in_reply_to_id,omitempty" db:"in_reply_to_id"`
+	ConversationID string       `json:"conversation_id" db:"conversation_id"`
+	CreatedAt      string       `json:"created_at" db:"created_at"`
+	LikeCount      int          `json:"like_count" db:"like_count"`
+	CommentCount   int          `json:"comment_count" db:"comment_count"`
+	Hashtags       []string     `json:"hashtags"`
+	Mentions       []Mention    `json:"mentions"`
+	Attachments    []Media      `json:"attachments"`
+	LikedByMe      *bool        `json:"liked_by_me,omitempty"`
+	Author         *UserSummary `json:"author,omitempty"`
+
+	RepostOf         *Post `json:"repost_of,omitempty"`
+	QuotedPost       *Post `json:"quoted_post,omitempty"`
+	QuoteUnavailable bool  `json:"quote_unavailable,omitempty"`
+}
+
+type Thread struct {
+	Ancestors   []Post `json:"ancestors"`
+	Post        Post   `json:"post"`
+	Descendants []Post `json:"descendants"`
+	Truncated   bool   `json:"truncated"`
+}
+
+type Feed struct {
+	Posts      []Post `json:"posts"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	NextOffset int    `json:"next_offset,omitempty"`
+}
+
+type Mention struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+}
+
+type Hashtag struct {
+	Tag       string `json:"tag"`
+	PostCount int    `json:"post_count"`
+}
+
+type UserSearchResult struct {
+	User
+	Score float64 `json:"score"`
+}
+
+type PostSearchResult struct {
+	Post
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type TrendingPost struct {
+	Post
+	Score float64 `json:"score"`
+}
+
+type TrendingHashtag struct {
+	Tag       string  `json:"tag"`
+	PostCount int     `json:"post_count"`
+	Score     float64 `json:"score"`
 }
 
 type Like struct {
-	ID     string `json:"id" db:"id"`
-	UserID string `json:"user_id" db:"user_id"`
-	PostID string `json:"post_id" db:"post_id"`
+	ID       string       `json:"id" db:"id"`
+	UserID   string       `json:"user_id" db:"user_id"`
+	PostID   string       `json:"post_id" db:"post_id"`
+	Reaction string       `json:"reaction" db:"reaction"`
+	Author   *UserSummary `json:"author,omitempty"`
+	Post     *PostSummary `json:"post,omitempty"`
+}
+
+type LikeState struct {
+	UserID string `json:"user_id"`
+	PostID string `json:"post_id"`
+	Liked  *bool  `json:"liked"`
+}
+
+type ReactionSummary struct {
+	PostID   string         `json:"post_id"`
+	Counts   map[string]int `json:"counts"`
+	Reactors []Like         `json:"reactors"`
+	Total    int            `json:"total"`
+	Limit    int            `json:"limit"`
+	Offset   int            `json:"offset"`
 }
 
 type Comment struct {
-	ID        string `json:"id" db:"id"`
-	UserID    string `json:"user_id" db:"user_id"`
-	PostID    string `json:"post_id" db:"post_id"`
-	Content   string `json:"content" db:"content"`
-	CreatedAt string `json:"created_at" db:"created_at"`
+	ID        string       `json:"id" db:"id"`
+	UserID    string       `json:"user_id" db:"user_id"`
+	PostID    string       `json:"post_id" db:"post_id"`
+	Content   string       `json:"content" db:"content"`
+	CreatedAt string       `json:"created_at" db:"created_at"`
+	Mentions  []Mention    `json:"mentions"`
+	Author    *UserSummary `json:"author,omitempty"`
+	Post      *PostSummary `json:"post,omitempty"`
 }
 
 type Follow struct {
-	ID          string `json:"id" db:"id"`
-	FollowerID  string `json:"follower_id" db:"follower_id"`
-	FollowingID string `json:"following_id" db:"following_id"`
-	CreatedAt   string `json:"created_at" db:"created_at"`
+	ID          string       `json:"id" db:"id"`
+	FollowerID  string       `json:"follower_id" db:"follower_id"`
+	FollowingID string       `json:"following_id" db:"following_id"`
+	CreatedAt   string       `json:"created_at" db:"created_at"`
+	Status      string       `json:"status,omitempty"`
+	Follower    *UserSummary `json:"follower,omitempty"`
+	Following   *UserSummary `json:"following,omitempty"`
+}
+
+type FollowRequest struct {
+	ID          string       `json:"id" db:"id"`
+	RequesterID string       `json:"requester_id" db:"requester_id"`
+	TargetID    string       `json:"target_id" db:"target_id"`
+	CreatedAt   string       `json:"created_at" db:"created_at"`
+	Requester   *UserSummary `json:"requester,omitempty"`
+}
+
+type Block struct {
+	ID        string       `json:"id" db:"id"`
+	BlockerID string       `json:"blocker_id" db:"blocker_id"`
+	BlockedID string       `json:"blocked_id" db:"blocked_id"`
+	CreatedAt string       `json:"created_at" db:"created_at"`
+	Blocked   *UserSummary `json:"blocked,omitempty"`
+}
+
+type Mute struct {
+	ID          string       `json:"id" db:"id"`
+	UserID      string       `json:"user_id" db:"user_id"`
+	MutedUserID string       `json:"muted_user_id" db:"muted_user_id"`
+	ExpiresAt   *string      `json:"expires_at" db:"expires_at"`
+	CreatedAt   string       `json:"created_at" db:"created_at"`
+	MutedUser   *UserSummary `json:"muted_user,omitempty"`
+}
+
+type MutedWord struct {
+	ID        string  `json:"id" db:"id"`
+	UserID    string  `json:"user_id" db:"user_id"`
+	Word      string  `json:"word" db:"word"`
+	ExpiresAt *string `json:"expires_at" db:"expires_at"`
+	CreatedAt string  `json:"created_at" db:"created_at"`
+}
+
+type Notification struct {
+	ID        string       `json:"id" db:"id"`
+	UserID    string       `json:"user_id" db:"user_id"`
+	ActorID   string       `json:"actor_id" db:"actor_id"`
+	Type      string       `json:"type" db:"type"`
+	PostID    string       `json:"post_id" db:"post_id"`
+	CommentID *string      `json:"comment_id" db:"comment_id"`
+	Read      bool         `json:"read" db:"read"`
+	CreatedAt string       `json:"created_at" db:"created_at"`
+	Actor     *UserSummary `json:"actor,omitempty"`
+}
+
+type UserSummary struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type PostSummary struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
 }
 
 type Response struct {
@@ -0,0 +1,50 @@
+package models
+
+import "encoding/json"
+
+// userJSON has the same fields as User without its MarshalJSON method.
+type userJSON User
+
+// userViewer is who a user is being rendered for. Only a verified identity
+// may be set here, never one taken from an unauthenticated header.
+type userViewer struct {
+	id    string
+	admin bool
+}
+
+// SetViewer records the verified viewer the user is rendered for, which
+// decides whether private fields and the birthday are included.
+func (u *User) SetViewer(viewerID string, admin bool) {
+	u.viewer = userViewer{id: viewerID, admin: admin}
+}
+
+// view returns the representation to render. Email and birthday visibility
+// are only kept for the user themselves and admins, and the birthday also
+// for whoever its visibility allows. Without SetViewer the user is rendered
+// as for an anonymous viewer, so forgetting to shape a user in a handler can
+// never leak them.
+func (u User) view() userJSON {
+	view := userJSON(u)
+	if u.viewer.admin || (u.viewer.id != "" && u.viewer.id == u.ID) {
+		return view
+	}
+
+	view.Email = ""
+	view.BirthdayVisibility = ""
+	follower := u.viewer.id != "" && u.FollowedByMe != nil && *u.FollowedByMe
+	if u.BirthdayVisibility != "public" && !(u.BirthdayVisibility == "followers" && follower) {
+		view.Birthday = ""
+	}
+	return view
+}
+
+func (u User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.view())
+}
+
+func (r UserSearchResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		userJSON
+		Score float64 `json:"score"`
+	}{r.User.view(), r.Score})
+}
@@ -2,22 +2,45 @@ package routes
 
 import (
 	"social-media-api/controllers"
+	"social-media-api/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(r *gin.Engine) {
-	userRoutes := r.Group("/users")
+	r.GET("/feed", middleware.RequireVerifiedAuth(), controllers.GetFeed)
+
+	userRoutes := r.Group("/users", middleware.ResolveUsername())
 	{
 		userRoutes.POST("", controllers.CreateUser)
 		userRoutes.GET("", controllers.GetAllUsers)
+		userRoutes.GET("/autocomplete", controllers.AutocompleteUsernames)
+		userRoutes.GET("/me/drafts", middleware.RequireVerifiedAuth(), controllers.GetMyDrafts)
+		userRoutes.GET("/me/scheduled", middleware.RequireVerifiedAuth(), controllers.GetMyScheduledPosts)
+		userRoutes.GET("/me/blocks", middleware.RequireVerifiedAuth(), controllers.GetMyBlocks)
+		userRoutes.GET("/me/mutes", middleware.RequireVerifiedAuth(), controllers.GetMyMutes)
+		userRoutes.GET("/me/muted-words", middleware.RequireVerifiedAuth(), controllers.GetMyMutedWords)
+		userRoutes.POST("/me/muted-words", middleware.RequireVerifiedAuth(), controllers.MuteWord)
+		userRoutes.DELETE("/me/muted-words/:word_id", middleware.RequireVerifiedAuth(), controllers.UnmuteWord)
+		userRoutes.GET("/me/notifications", middleware.RequireVerifiedAuth(), controllers.GetMyNotifications)
+		userRoutes.POST("/me/notifications/read", middleware.RequireVerifiedAuth(), controllers.MarkNotificationsRead)
+		userRoutes.GET("/by-username/:username", controllers.GetUserByUsername)
 		userRoutes.GET("/:id", controllers.GetUserByID)
-		userRoutes.PUT("/:id", controllers.UpdateUser)
-		userRoutes.DELETE("/:id", controllers.DeleteUser)
+		userRoutes.PUT("/:id", middleware.RequireVerifiedAuth(), controllers.UpdateUser)
+		userRoutes.DELETE("/:id", middleware.RequireVerifiedAuth(), controllers.DeleteUser)
 		userRoutes.GET("/:id/posts", controllers.GetPostsByUserID)
 		userRoutes.GET("/:id/likes", controllers.GetLikesByUserID)
 		userRoutes.GET("/:id/followers", controllers.GetFollowers)
 		userRoutes.GET("/:id/following", controllers.GetFollowing)
+		userRoutes.GET("/:id/feed", controllers.GetUserFeed)
+		userRoutes.GET("/:id/mentions", controllers.GetMentionsByUserID)
+		userRoutes.GET("/:id/follow-requests", middleware.RequireVerifiedAuth(), controllers.GetFollowRequests)
+		userRoutes.POST("/:id/follow-requests/:requester_id/approve", middleware.RequireVerifiedAuth(), controllers.ApproveFollowRequest)
+		userRoutes.POST("/:id/follow-requests/:requester_id/reject", middleware.RequireVerifiedAuth(), controllers.RejectFollowRequest)
+		userRoutes.POST("/:id/block", middleware.RequireVerifiedAuth(), controllers.BlockUser)
+		userRoutes.DELETE("/:id/block", middleware.RequireVerifiedAuth(), controllers.UnblockUser)
+		userRoutes.POST("/:id/mute", middleware.RequireVerifiedAuth(), controllers.MuteUser)
+		userRoutes.DELETE("/:id/mute", middleware.RequireVerifiedAuth(), controllers.UnmuteUser)
 	}
 
 	postRoutes := r.Group("/posts")
@@ -26,13 +49,35 @@ func SetupRoutes(r *gin.Engine) {
 		postRoutes.GET("", controllers.GetAllPosts)
 		postRoutes.GET("/:id", controllers.GetPostByID)
 		postRoutes.DELETE("/:id", controllers.DeletePost)
+		postRoutes.POST("/:id/publish", middleware.RequireVerifiedAuth(), controllers.PublishPost)
+		postRoutes.POST("/:id/repost", middleware.RequireAuth(), controllers.Repost)
+		postRoutes.DELETE("/:id/repost", middleware.RequireAuth(), controllers.Unrepost)
+		postRoutes.GET("/:id/reposts", controllers.GetReposts)
+		postRoutes.GET("/:id/thread", controllers.GetThread)
 		postRoutes.GET("/:id/likes", controllers.GetLikesByPostID)
+		postRoutes.GET("/:id/reactions", controllers.GetReactionsByPostID)
 		postRoutes.GET("/:id/comments", controllers.GetCommentsByPostID)
 	}
 
+	mediaRoutes := r.Group("/media")
+	{
+		mediaRoutes.POST("", middleware.RequireAuth(), controllers.UploadMedia)
+		mediaRoutes.GET("/:id", controllers.GetMedia)
+		mediaRoutes.GET("/:id/file", controllers.GetMediaFile)
+		mediaRoutes.GET("/:id/thumbnail", controllers.GetMediaThumbnail)
+	}
+
 	likeRoutes := r.Group("/likes")
 	{
 		likeRoutes.POST("", controllers.CreateLike)
+		likeRoutes.PUT("", controllers.SetLike)
+		likeRoutes.DELETE("", controllers.DeleteLike)
+	}
+
+	reactionRoutes := r.Group("/reactions")
+	{
+		reactionRoutes.PUT("", controllers.SetReaction)
+		reactionRoutes.DELETE("", controllers.DeleteReaction)
 	}
 
 	commentRoutes := r.Group("/comments")
@@ -40,6 +85,24 @@ func SetupRoutes(r *gin.Engine) {
 		commentRoutes.POST("", controllers.CreateComment)
 	}
 
+	searchRoutes := r.Group("/search")
+	{
+		searchRoutes.GET("/posts", controllers.SearchPosts)
+		searchRoutes.GET("/users", controllers.SearchUsers)
+	}
+
+	hashtagRoutes := r.Group("/hashtags")
+	{
+		hashtagRoutes.GET("", controllers.SearchHashtags)
+		hashtagRoutes.GET("/:tag/posts", controllers.GetPostsByHashtag)
+	}
+
+	trendingRoutes := r.Group("/trending")
+	{
+		trendingRoutes.GET("/posts", controllers.GetTrendingPosts)
+		trendingRoutes.GET("/hashtags", controllers.GetTrendingHashtags)
+	}
+
 	followRoutes := r.Group("/follows")
 	{
 		followRoutes.POST("", controllers.CreateFollow)
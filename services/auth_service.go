@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"social-media-api/config"
+	"social-media-api/utils"
+)
+
+func authSettings() config.AuthConfig {
+	if config.AppConfig != nil {
+		return config.AppConfig.Auth
+	}
+	return config.AuthConfig{TokenTTLHours: 720}
+}
+
+// IssueToken signs a bearer token for userID. Tokens are handed out by an
+// operator or a login service; nothing in this API issues them on request.
+func IssueToken(userID string) (string, error) {
+	if _, err := NewUserService().GetUserByID(userID); err != nil {
+		return "", err
+	}
+
+	settings := authSettings()
+	if settings.TokenSecret == "" {
+		return "", errors.New("AUTH_TOKEN_SECRET is not set")
+	}
+	expiresAt := time.Now().Add(time.Duration(settings.TokenTTLHours) * time.Hour)
+	return utils.SignToken(settings.TokenSecret, userID, expiresAt), nil
+}
+
+func VerifyToken(token string) (string, error) {
+	return utils.VerifyToken(authSettings().TokenSecret, token, time.Now())
+}
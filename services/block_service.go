@@ -0,0 +1,169 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/google/uuid"
+)
+
+type BlockService struct{}
+
+func NewBlockService() *BlockService {
+	return &BlockService{}
+}
+
+// BlockUser blocks blockedID on behalf of blockerID and removes any follows
+// and pending follow requests between the two accounts.
+func (s *BlockService) BlockUser(blockerID, blockedID string) (*models.Block, error) {
+	if blockerID == blockedID {
+		return nil, errors.New("cannot block yourself")
+	}
+
+	var blockerExists bool
+	checkBlockerQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkBlockerQuery, blockerID).Scan(&blockerExists)
+	if err != nil {
+		return nil, err
+	}
+	if !blockerExists {
+		return nil, errors.New("blocker user not found")
+	}
+
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err = database.DB.QueryRow(checkUserQuery, blockedID).Scan(&userExists)
+	if err != nil {
+		return nil, err
+	}
+	if !userExists {
+		return nil, errors.New("user not found")
+	}
+
+	block := &models.Block{
+		ID:        uuid.New().String(),
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO blocks (id, blocker_id, blocked_id, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(query, block.ID, block.BlockerID, block.BlockedID, block.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, errors.New("user already blocked")
+		}
+		return nil, err
+	}
+
+	unfollowed, err := unfollowEachOther(tx, blockerID, blockedID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestQuery := `DELETE FROM follow_requests
+		WHERE (requester_id = $1 AND target_id = $2) OR (requester_id = $2 AND target_id = $1)`
+	if _, err := tx.Exec(requestQuery, blockerID, blockedID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	timelineStore.RemoveAuthor(blockerID, blockedID)
+	timelineStore.RemoveAuthor(blockedID, blockerID)
+	for _, followingID := range unfollowed {
+		followerCountChanged(followingID, -1)
+	}
+	return block, nil
+}
+
+// unfollowEachOther removes the follows between two users in either
+// direction and returns the users who lost a follower.
+func unfollowEachOther(tx *sql.Tx, userID, otherID string) ([]string, error) {
+	query := `DELETE FROM follows
+		WHERE (follower_id = $1 AND following_id = $2) OR (follower_id = $2 AND following_id = $1)
+		RETURNING following_id`
+	rows, err := tx.Query(query, userID, otherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var unfollowed []string
+	for rows.Next() {
+		var followingID string
+		if err := rows.Scan(&followingID); err != nil {
+			return nil, err
+		}
+		unfollowed = append(unfollowed, followingID)
+	}
+
+	return unfollowed, rows.Err()
+}
+
+func (s *BlockService) UnblockUser(blockerID, blockedID string) error {
+	query := `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	result, err := database.DB.Exec(query, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("block not found")
+	}
+
+	return nil
+}
+
+func (s *BlockService) GetBlocks(blockerID string) ([]models.Block, error) {
+	query := `SELECT id, blocker_id, blocked_id, created_at FROM blocks WHERE blocker_id = $1 ORDER BY created_at DESC`
+	rows, err := database.DB.Query(query, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []models.Block{}
+	for rows.Next() {
+		var block models.Block
+		err := rows.Scan(&block.ID, &block.BlockerID, &block.BlockedID, &block.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	blockedIDs := make([]string, len(blocks))
+	for i, block := range blocks {
+		blockedIDs[i] = block.BlockedID
+	}
+	users, err := loadUserSummaries(blockedIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blocks {
+		blocks[i].Blocked = users[blocks[i].BlockedID]
+	}
+
+	return blocks, nil
+}
@@ -31,41 +31,56 @@ func (s *CommentService) CreateComment(comment *models.Comment) error {
 		return errors.New("user not found")
 	}
 
-	var postExists bool
-	checkPostQuery := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`
-	err = database.DB.QueryRow(checkPostQuery, comment.PostID).Scan(&postExists)
-	if err != nil {
+	if err := checkPostVisible(comment.UserID, comment.PostID); err != nil {
 		return err
 	}
-	if !postExists {
-		return errors.New("post not found")
-	}
 
 	comment.ID = uuid.New().String()
 	comment.CreatedAt = time.Now().Format(time.RFC3339)
 
+	mentions, err := resolveMentions(comment.UserID, comment.Content)
+	if err != nil {
+		return err
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO comments (id, user_id, post_id, content, created_at) VALUES ($1, $2, $3, $4, $5)`
-	_, err = database.DB.Exec(query, comment.ID, comment.UserID, comment.PostID, comment.Content, comment.CreatedAt)
+	_, err = tx.Exec(query, comment.ID, comment.UserID, comment.PostID, comment.Content, comment.CreatedAt)
 	if err != nil {
 		return err
 	}
 
+	if err := storeMentions(tx, "", comment.ID, mentions); err != nil {
+		return err
+	}
+
+	if err := notifyMentions(tx, comment.UserID, comment.PostID, comment.ID, mentions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	comment.Mentions = mentions
 	return nil
 }
 
-func (s *CommentService) GetCommentsByPostID(postID string) ([]models.Comment, error) {
-	var postExists bool
-	checkPostQuery := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`
-	err := database.DB.QueryRow(checkPostQuery, postID).Scan(&postExists)
-	if err != nil {
+func (s *CommentService) GetCommentsByPostID(viewerID, postID string) ([]models.Comment, error) {
+	if err := checkPostVisible(viewerID, postID); err != nil {
 		return nil, err
 	}
-	if !postExists {
-		return nil, errors.New("post not found")
-	}
 
-	query := `SELECT id, user_id, post_id, content, created_at FROM comments WHERE post_id = $1 ORDER BY created_at ASC`
-	rows, err := database.DB.Query(query, postID)
+	query := `SELECT id, user_id, post_id, content, created_at FROM comments
+		WHERE post_id = $1 AND ` + notBlockedFilter("user_id", "$2") + `
+			AND ` + notMutedFilter("user_id", "to_tsvector('simple', content)", "$2") + `
+		ORDER BY created_at ASC`
+	rows, err := database.DB.Query(query, postID, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -83,3 +98,10 @@ func (s *CommentService) GetCommentsByPostID(postID string) ([]models.Comment, e
 
 	return comments, nil
 }
+
+func (s *CommentService) PrepareComments(comments []models.Comment, includes map[string]bool) error {
+	if err := attachCommentMentions(comments); err != nil {
+		return err
+	}
+	return s.ExpandComments(comments, includes)
+}
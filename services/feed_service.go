@@ -0,0 +1,263 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"social-media-api/database"
+	"social-media-api/models"
+	"social-media-api/utils"
+)
+
+type FeedService struct{}
+
+func NewFeedService() *FeedService {
+	return &FeedService{}
+}
+
+// GetHomeFeed serves userID's timeline from the precomputed store, merging
+// in posts from high-follower accounts that are not fanned out on write. It
+// falls back to querying the database when the store cannot cover the page.
+// Mutes are applied for viewerID, who is reading the timeline.
+func (s *FeedService) GetHomeFeed(viewerID, userID, cursor string, limit int) (*models.Feed, error) {
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
+	if err != nil {
+		return nil, err
+	}
+	if !userExists {
+		return nil, errors.New("user not found")
+	}
+
+	var before *TimelineEntry
+	if cursor != "" {
+		createdAt, id, err := utils.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+		if err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+		before = &TimelineEntry{PostID: id, CreatedAt: parsed}
+	}
+
+	maxAge := time.Duration(timelineSettings().MaxAgeSeconds) * time.Second
+	if !timelineStore.Has(userID, maxAge) {
+		if err := rebuildTimeline(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	posts, ok, err := cachedTimelinePosts(userID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		posts, err := s.queryFeed(userID, before, limit+1, false)
+		if err != nil {
+			return nil, err
+		}
+		return buildMutedFeed(viewerID, posts, limit)
+	}
+
+	merged, err := s.queryFeed(userID, before, limit+1, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildMutedFeed(viewerID, mergePosts(posts, merged), limit)
+}
+
+// cachedTimelinePosts loads up to limit posts from the user's stored
+// timeline. Entries for posts that were deleted or are no longer visible to
+// the user, such as reposts of an account they have since blocked, are
+// dropped from the store and the range is read again, so a page is never
+// cut short by them. ok is false when the store cannot fill the page.
+func cachedTimelinePosts(userID string, before *TimelineEntry, limit int) ([]models.Post, bool, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		entries, ok := timelineStore.Range(userID, before, limit)
+		if !ok {
+			return nil, false, nil
+		}
+
+		ids := make([]string, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.PostID
+		}
+		cached, err := loadPostsByIDs(userID, ids)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var posts []models.Post
+		missing := false
+		for _, entry := range entries {
+			post, found := cached[entry.PostID]
+			if !found {
+				timelineStore.Remove(userID, entry.PostID)
+				missing = true
+				continue
+			}
+			posts = append(posts, post)
+		}
+		if !missing {
+			return posts, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// queryFeed reads the timeline directly from posts and follows. With
+// highFollowerOnly set, only followed accounts above the fan-out limit
+// are included.
+func (s *FeedService) queryFeed(userID string, before *TimelineEntry, limit int, highFollowerOnly bool) ([]models.Post, error) {
+	var query string
+	args := []interface{}{userID}
+
+	if highFollowerOnly {
+		query = `SELECT ` + postColumns + ` FROM posts WHERE user_id IN (
+			SELECT f.following_id FROM follows f JOIN users u ON u.id = f.following_id
+			WHERE f.follower_id = $1 AND u.follower_count > $2)`
+		args = append(args, timelineSettings().FanoutLimit)
+	} else {
+		query = `SELECT ` + postColumns + ` FROM posts
+			WHERE (user_id = $1 OR user_id IN (SELECT following_id FROM follows WHERE follower_id = $1))`
+	}
+	query += ` AND ` + visiblePostFilter("posts", "$1")
+
+	if before != nil {
+		query += fmt.Sprintf(` AND (created_at, id) < ($%d::timestamp, $%d)`, len(args)+1, len(args)+2)
+		args = append(args, before.CreatedAt.Format(time.RFC3339Nano), before.PostID)
+	}
+
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+func mergePosts(a, b []models.Post) []models.Post {
+	merged := make([]models.Post, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+
+	for len(a) > 0 || len(b) > 0 {
+		var next models.Post
+		if len(b) == 0 || (len(a) > 0 && newerPost(a[0], b[0])) {
+			next, a = a[0], a[1:]
+		} else {
+			next, b = b[0], b[1:]
+		}
+
+		if !seen[next.ID] {
+			seen[next.ID] = true
+			merged = append(merged, next)
+		}
+	}
+
+	return merged
+}
+
+func newerPost(a, b models.Post) bool {
+	entryA, errA := timelineEntry(a)
+	entryB, errB := timelineEntry(b)
+	if errA != nil || errB != nil {
+		return a.CreatedAt > b.CreatedAt
+	}
+	return newerEntry(entryA, entryB)
+}
+
+func buildFeed(posts []models.Post, limit int) *models.Feed {
+	feed := &models.Feed{Posts: posts}
+	if feed.Posts == nil {
+		feed.Posts = []models.Post{}
+	}
+
+	if len(feed.Posts) > limit {
+		feed.Posts = feed.Posts[:limit]
+		last := feed.Posts[limit-1]
+		feed.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return feed
+}
+
+// buildMutedFeed builds a page and then drops what viewerID has muted. The
+// cursor still points past the last unfiltered post, so pages may come back
+// short but never skip anything.
+func buildMutedFeed(viewerID string, posts []models.Post, limit int) (*models.Feed, error) {
+	feed := buildFeed(posts, limit)
+
+	filtered, err := filterMutedPosts(viewerID, feed.Posts)
+	if err != nil {
+		return nil, err
+	}
+	feed.Posts = filtered
+
+	return feed, nil
+}
+
+// GetRankedFeed ranks userID's timeline by score, applying viewerID's mutes.
+func (s *FeedService) GetRankedFeed(viewerID, userID string, limit, offset int) (*models.Feed, error) {
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
+	if err != nil {
+		return nil, err
+	}
+	if !userExists {
+		return nil, errors.New("user not found")
+	}
+
+	weights := rankingSettings()
+	candidates, err := s.queryFeed(userID, nil, weights.CandidateLimit, false)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err = filterMutedPosts(viewerID, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := make([]string, len(candidates))
+	for i, post := range candidates {
+		authorIDs[i] = post.UserID
+	}
+	affinity, err := loadAffinity(userID, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := RankPosts(candidates, affinity, weights, time.Now())
+
+	feed := &models.Feed{Posts: []models.Post{}}
+	if offset < len(ranked) {
+		end := offset + limit
+		if end < len(ranked) {
+			feed.NextOffset = end
+		} else {
+			end = len(ranked)
+		}
+		feed.Posts = ranked[offset:end]
+	}
+
+	return feed, nil
+}
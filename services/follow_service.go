@@ -1,6 +1,7 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
 	"strings"
 	"time"
@@ -32,19 +33,31 @@ func (s *FollowService) CreateFollow(follow *models.Follow) error {
 		return errors.New("follower user not found")
 	}
 
-	var followingExists bool
-	checkFollowingQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
-	err = database.DB.QueryRow(checkFollowingQuery, follow.FollowingID).Scan(&followingExists)
+	var isPrivate bool
+	checkFollowingQuery := `SELECT is_private FROM users WHERE id = $1`
+	err = database.DB.QueryRow(checkFollowingQuery, follow.FollowingID).Scan(&isPrivate)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("following user not found")
+		}
+		return err
+	}
+
+	blocked, err := isBlockedBetween(follow.FollowerID, follow.FollowingID)
 	if err != nil {
 		return err
 	}
-	if !followingExists {
-		return errors.New("following user not found")
+	if blocked {
+		return errors.New("cannot follow this user")
 	}
 
 	follow.ID = uuid.New().String()
 	follow.CreatedAt = time.Now().Format(time.RFC3339)
 
+	if isPrivate {
+		return s.requestFollow(follow)
+	}
+
 	query := `INSERT INTO follows (id, follower_id, following_id, created_at) VALUES ($1, $2, $3, $4)`
 	_, err = database.DB.Exec(query, follow.ID, follow.FollowerID, follow.FollowingID, follow.CreatedAt)
 	if err != nil {
@@ -54,30 +67,39 @@ func (s *FollowService) CreateFollow(follow *models.Follow) error {
 		return err
 	}
 
+	follow.Status = "following"
+	timelineStore.Invalidate(follow.FollowerID)
+	followerCountChanged(follow.FollowingID, 1)
 	return nil
 }
 
-func (s *FollowService) DeleteFollow(followerID, followingID string) error {
-	var followExists bool
+// requestFollow records a pending request to follow a private account. The
+// follow itself is created once the account owner approves it.
+func (s *FollowService) requestFollow(follow *models.Follow) error {
+	var alreadyFollowing bool
 	checkQuery := `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = $2)`
-	err := database.DB.QueryRow(checkQuery, followerID, followingID).Scan(&followExists)
+	err := database.DB.QueryRow(checkQuery, follow.FollowerID, follow.FollowingID).Scan(&alreadyFollowing)
 	if err != nil {
 		return err
 	}
-	if !followExists {
-		return errors.New("follow relationship not found")
+	if alreadyFollowing {
+		return errors.New("already following this user")
 	}
 
-	query := `DELETE FROM follows WHERE follower_id = $1 AND following_id = $2`
-	_, err = database.DB.Exec(query, followerID, followingID)
+	query := `INSERT INTO follow_requests (id, requester_id, target_id, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = database.DB.Exec(query, follow.ID, follow.FollowerID, follow.FollowingID, follow.CreatedAt)
 	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return errors.New("follow request already sent")
+		}
 		return err
 	}
 
+	follow.Status = "requested"
 	return nil
 }
 
-func (s *FollowService) GetFollowers(userID string) ([]models.Follow, error) {
+func (s *FollowService) GetFollowRequests(userID string) ([]models.FollowRequest, error) {
 	var userExists bool
 	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
 	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
@@ -88,6 +110,168 @@ func (s *FollowService) GetFollowers(userID string) ([]models.Follow, error) {
 		return nil, errors.New("user not found")
 	}
 
+	query := `SELECT id, requester_id, target_id, created_at FROM follow_requests WHERE target_id = $1 ORDER BY created_at DESC`
+	rows, err := database.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []models.FollowRequest{}
+	for rows.Next() {
+		var request models.FollowRequest
+		err := rows.Scan(&request.ID, &request.RequesterID, &request.TargetID, &request.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	requesterIDs := make([]string, len(requests))
+	for i, request := range requests {
+		requesterIDs[i] = request.RequesterID
+	}
+	users, err := loadUserSummaries(requesterIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range requests {
+		requests[i].Requester = users[requests[i].RequesterID]
+	}
+
+	return requests, nil
+}
+
+// ApproveFollowRequest turns a pending request into a follow.
+func (s *FollowService) ApproveFollowRequest(userID, requesterID string) (*models.Follow, error) {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM follow_requests WHERE target_id = $1 AND requester_id = $2`, userID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, errors.New("follow request not found")
+	}
+
+	follow := &models.Follow{
+		ID:          uuid.New().String(),
+		FollowerID:  requesterID,
+		FollowingID: userID,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Status:      "following",
+	}
+	query := `INSERT INTO follows (id, follower_id, following_id, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (follower_id, following_id) DO NOTHING`
+	result, err = tx.Exec(query, follow.ID, follow.FollowerID, follow.FollowingID, follow.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	timelineStore.Invalidate(requesterID)
+	followerCountChanged(userID, int(added))
+	return follow, nil
+}
+
+func (s *FollowService) RejectFollowRequest(userID, requesterID string) error {
+	query := `DELETE FROM follow_requests WHERE target_id = $1 AND requester_id = $2`
+	result, err := database.DB.Exec(query, userID, requesterID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("follow request not found")
+	}
+
+	return nil
+}
+
+// approveAllFollowRequests converts every pending request for userID into a
+// follow and returns the new followers.
+func approveAllFollowRequests(tx *sql.Tx, userID string) ([]string, error) {
+	query := `INSERT INTO follows (id, follower_id, following_id, created_at)
+		SELECT id, requester_id, target_id, $2 FROM follow_requests WHERE target_id = $1
+		ON CONFLICT (follower_id, following_id) DO NOTHING`
+	if _, err := tx.Exec(query, userID, time.Now().Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(`DELETE FROM follow_requests WHERE target_id = $1 RETURNING requester_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requesters []string
+	for rows.Next() {
+		var requesterID string
+		if err := rows.Scan(&requesterID); err != nil {
+			return nil, err
+		}
+		requesters = append(requesters, requesterID)
+	}
+
+	return requesters, rows.Err()
+}
+
+func (s *FollowService) DeleteFollow(followerID, followingID string) error {
+	var followExists bool
+	checkQuery := `SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = $2)`
+	err := database.DB.QueryRow(checkQuery, followerID, followingID).Scan(&followExists)
+	if err != nil {
+		return err
+	}
+	if !followExists {
+		// Unfollowing a private account before approval withdraws the request.
+		cancelQuery := `DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2`
+		result, err := database.DB.Exec(cancelQuery, followerID, followingID)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			return nil
+		}
+		return errors.New("follow relationship not found")
+	}
+
+	query := `DELETE FROM follows WHERE follower_id = $1 AND following_id = $2`
+	_, err = database.DB.Exec(query, followerID, followingID)
+	if err != nil {
+		return err
+	}
+
+	timelineStore.RemoveAuthor(followerID, followingID)
+	followerCountChanged(followingID, -1)
+	return nil
+}
+
+func (s *FollowService) GetFollowers(viewerID, userID string) ([]models.Follow, error) {
+	if err := checkUserVisible(viewerID, userID); err != nil {
+		return nil, err
+	}
+
 	query := `SELECT id, follower_id, following_id, created_at FROM follows WHERE following_id = $1 ORDER BY created_at DESC`
 	rows, err := database.DB.Query(query, userID)
 	if err != nil {
@@ -108,16 +292,10 @@ func (s *FollowService) GetFollowers(userID string) ([]models.Follow, error) {
 	return followers, nil
 }
 
-func (s *FollowService) GetFollowing(userID string) ([]models.Follow, error) {
-	var userExists bool
-	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
-	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
-	if err != nil {
+func (s *FollowService) GetFollowing(viewerID, userID string) ([]models.Follow, error) {
+	if err := checkUserVisible(viewerID, userID); err != nil {
 		return nil, err
 	}
-	if !userExists {
-		return nil, errors.New("user not found")
-	}
 
 	query := `SELECT id, follower_id, following_id, created_at FROM follows WHERE follower_id = $1 ORDER BY created_at DESC`
 	rows, err := database.DB.Query(query, userID)
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"database/sql"
+
+	"social-media-api/database"
+	"social-media-api/models"
+	"social-media-api/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type HashtagService struct{}
+
+func NewHashtagService() *HashtagService {
+	return &HashtagService{}
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func indexPostHashtags(db execer, postID, content string) error {
+	if _, err := db.Exec(`DELETE FROM post_hashtags WHERE post_id = $1`, postID); err != nil {
+		return err
+	}
+
+	for _, tag := range utils.ExtractHashtags(content) {
+		upsertQuery := `INSERT INTO hashtags (id, tag) VALUES ($1, $2) ON CONFLICT (tag) DO NOTHING`
+		if _, err := db.Exec(upsertQuery, uuid.New().String(), tag); err != nil {
+			return err
+		}
+
+		linkQuery := `INSERT INTO post_hashtags (post_id, hashtag_id)
+			SELECT $1, id FROM hashtags WHERE tag = $2
+			ON CONFLICT DO NOTHING`
+		if _, err := db.Exec(linkQuery, postID, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReindexHashtags rebuilds post_hashtags from the content of every post.
+func ReindexHashtags() error {
+	rows, err := database.DB.Query(`SELECT id, content FROM posts`)
+	if err != nil {
+		return err
+	}
+
+	type postContent struct{ id, content string }
+	var posts []postContent
+	for rows.Next() {
+		var post postContent
+		if err := rows.Scan(&post.id, &post.content); err != nil {
+			rows.Close()
+			return err
+		}
+		posts = append(posts, post)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, post := range posts {
+		if err := indexPostHashtags(tx, post.id, post.content); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func attachHashtags(posts []models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	query := `SELECT ph.post_id, h.tag FROM post_hashtags ph
+		JOIN hashtags h ON h.id = ph.hashtag_id
+		WHERE ph.post_id = ANY($1)
+		ORDER BY h.tag`
+	rows, err := database.DB.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tags := make(map[string][]string)
+	for rows.Next() {
+		var postID, tag string
+		if err := rows.Scan(&postID, &tag); err != nil {
+			return err
+		}
+		tags[postID] = append(tags[postID], tag)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range posts {
+		posts[i].Hashtags = tags[posts[i].ID]
+		if posts[i].Hashtags == nil {
+			posts[i].Hashtags = []string{}
+		}
+	}
+
+	return nil
+}
+
+func (s *HashtagService) GetPostsByHashtag(viewerID, tag string, limit, offset int) ([]models.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts
+		WHERE id IN (
+			SELECT ph.post_id FROM post_hashtags ph
+			JOIN hashtags h ON h.id = ph.hashtag_id
+			WHERE h.tag = $1)
+		AND ` + visiblePostFilter("posts", "$4") + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := database.DB.Query(query, utils.NormalizeHashtag(tag), limit, offset, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+// SearchHashtags autocompletes hashtags by prefix. Like trending, only
+// published public posts from public accounts are counted, so tags used
+// solely in drafts or restricted posts are not suggested.
+func (s *HashtagService) SearchHashtags(prefix string, limit int) ([]models.Hashtag, error) {
+	query := `SELECT h.tag, COUNT(ph.post_id) AS post_count FROM hashtags h
+		JOIN post_hashtags ph ON ph.hashtag_id = h.id
+		JOIN posts p ON p.id = ph.post_id
+		WHERE h.tag LIKE $1 || '%'
+		AND p.status = 'published' AND p.visibility = 'public' AND p.user_id NOT IN (SELECT id FROM users WHERE is_private)
+		GROUP BY h.tag
+		ORDER BY post_count DESC, h.tag
+		LIMIT $2`
+	rows, err := database.DB.Query(query, utils.EscapeLike(utils.NormalizeHashtag(prefix)), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashtags := []models.Hashtag{}
+	for rows.Next() {
+		var hashtag models.Hashtag
+		if err := rows.Scan(&hashtag.Tag, &hashtag.PostCount); err != nil {
+			return nil, err
+		}
+		hashtags = append(hashtags, hashtag)
+	}
+
+	return hashtags, rows.Err()
+}
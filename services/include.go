@@ -0,0 +1,197 @@
+package services
+
+import (
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/lib/pq"
+)
+
+func loadUserSummaries(ids []string) (map[string]*models.UserSummary, error) {
+	summaries := make(map[string]*models.UserSummary)
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	query := `SELECT id, username FROM users WHERE id = ANY($1)`
+	rows, err := database.DB.Query(query, pq.Array(uniqueIDs(ids)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.UserSummary
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, err
+		}
+		summaries[user.ID] = &user
+	}
+
+	return summaries, rows.Err()
+}
+
+func loadPostSummaries(ids []string) (map[string]*models.PostSummary, error) {
+	summaries := make(map[string]*models.PostSummary)
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	query := `SELECT id, user_id, content, created_at FROM posts WHERE id = ANY($1)`
+	rows, err := database.DB.Query(query, pq.Array(uniqueIDs(ids)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post models.PostSummary
+		if err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt); err != nil {
+			return nil, err
+		}
+		summaries[post.ID] = &post
+	}
+
+	return summaries, rows.Err()
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func (s *PostService) ExpandPosts(posts []models.Post, includes map[string]bool) error {
+	if !includes["author"] || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.UserID
+	}
+
+	authors, err := loadUserSummaries(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		posts[i].Author = authors[posts[i].UserID]
+	}
+
+	return nil
+}
+
+func (s *CommentService) ExpandComments(comments []models.Comment, includes map[string]bool) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	if includes["author"] {
+		ids := make([]string, len(comments))
+		for i, comment := range comments {
+			ids[i] = comment.UserID
+		}
+
+		authors, err := loadUserSummaries(ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range comments {
+			comments[i].Author = authors[comments[i].UserID]
+		}
+	}
+
+	if includes["post"] {
+		ids := make([]string, len(comments))
+		for i, comment := range comments {
+			ids[i] = comment.PostID
+		}
+
+		posts, err := loadPostSummaries(ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range comments {
+			comments[i].Post = posts[comments[i].PostID]
+		}
+	}
+
+	return nil
+}
+
+func (s *LikeService) ExpandLikes(likes []models.Like, includes map[string]bool) error {
+	if len(likes) == 0 {
+		return nil
+	}
+
+	if includes["author"] {
+		ids := make([]string, len(likes))
+		for i, like := range likes {
+			ids[i] = like.UserID
+		}
+
+		authors, err := loadUserSummaries(ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range likes {
+			likes[i].Author = authors[likes[i].UserID]
+		}
+	}
+
+	if includes["post"] {
+		ids := make([]string, len(likes))
+		for i, like := range likes {
+			ids[i] = like.PostID
+		}
+
+		posts, err := loadPostSummaries(ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range likes {
+			likes[i].Post = posts[likes[i].PostID]
+		}
+	}
+
+	return nil
+}
+
+func (s *FollowService) ExpandFollows(follows []models.Follow, includes map[string]bool) error {
+	if len(follows) == 0 || (!includes["follower"] && !includes["following"]) {
+		return nil
+	}
+
+	ids := make([]string, 0, len(follows)*2)
+	for _, follow := range follows {
+		ids = append(ids, follow.FollowerID, follow.FollowingID)
+	}
+
+	users, err := loadUserSummaries(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range follows {
+		if includes["follower"] {
+			follows[i].Follower = users[follows[i].FollowerID]
+		}
+		if includes["following"] {
+			follows[i].Following = users[follows[i].FollowingID]
+		}
+	}
+
+	return nil
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"errors"
 	"strings"
+	"time"
 
+	"social-media-api/config"
 	"social-media-api/database"
 	"social-media-api/models"
 
@@ -12,6 +14,8 @@ import (
 
 type LikeService struct{}
 
+const DefaultReaction = "like"
+
 func NewLikeService() *LikeService {
 	return &LikeService{}
 }
@@ -27,23 +31,26 @@ func (s *LikeService) CreateLike(like *models.Like) error {
 		return errors.New("user not found")
 	}
 
-	var postExists bool
-	checkPostQuery := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`
-	err = database.DB.QueryRow(checkPostQuery, like.PostID).Scan(&postExists)
-	if err != nil {
+	if err := checkPostVisible(like.UserID, like.PostID); err != nil {
 		return err
 	}
-	if !postExists {
-		return errors.New("post not found")
-	}
 
 	like.ID = uuid.New().String()
+	like.Reaction = DefaultReaction
 
-	query := `INSERT INTO likes (id, user_id, post_id) VALUES ($1, $2, $3)`
-	_, err = database.DB.Exec(query, like.ID, like.UserID, like.PostID)
+	query := `INSERT INTO likes (id, user_id, post_id, reaction, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err = database.DB.Exec(query, like.ID, like.UserID, like.PostID, like.Reaction, time.Now().Format(time.RFC3339))
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "UNIQUE constraint") {
-			return errors.New("satu user hanya boleh like satu post satu kali")
+			var reaction string
+			query := `SELECT reaction FROM likes WHERE user_id = $1 AND post_id = $2`
+			if err := database.DB.QueryRow(query, like.UserID, like.PostID).Scan(&reaction); err != nil {
+				return err
+			}
+			if reaction != DefaultReaction {
+				return errors.New("already reacted to this post")
+			}
+			return errors.New("already liked this post")
 		}
 		return err
 	}
@@ -51,18 +58,170 @@ func (s *LikeService) CreateLike(like *models.Like) error {
 	return nil
 }
 
-func (s *LikeService) GetLikesByPostID(postID string) ([]models.Like, error) {
-	var postExists bool
-	checkPostQuery := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`
-	err := database.DB.QueryRow(checkPostQuery, postID).Scan(&postExists)
+// SetLike likes or unlikes postID. It only touches the "like" reaction: an
+// existing reaction of another type is neither replaced nor removed.
+func (s *LikeService) SetLike(userID, postID string, liked bool) error {
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errors.New("user not found")
+	}
+
+	if err := checkPostVisible(userID, postID); err != nil {
+		return err
+	}
+
+	if !liked {
+		query := `DELETE FROM likes WHERE user_id = $1 AND post_id = $2 AND reaction = $3`
+		_, err := database.DB.Exec(query, userID, postID, DefaultReaction)
+		return err
+	}
+
+	query := `INSERT INTO likes (id, user_id, post_id, reaction, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, post_id) DO NOTHING`
+	_, err = database.DB.Exec(query, uuid.New().String(), userID, postID, DefaultReaction, time.Now().Format(time.RFC3339))
+	return err
+}
+
+func (s *LikeService) SetReaction(like *models.Like) error {
+	if like.Reaction == "" {
+		return errors.New("reaction is required")
+	}
+	if !isAllowedReaction(like.Reaction) {
+		return errors.New("unsupported reaction")
+	}
+
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkUserQuery, like.UserID).Scan(&userExists)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errors.New("user not found")
+	}
+
+	if err := checkPostVisible(like.UserID, like.PostID); err != nil {
+		return err
+	}
+
+	if err := s.upsertReaction(like.UserID, like.PostID, like.Reaction); err != nil {
+		return err
+	}
+
+	query := `SELECT id FROM likes WHERE user_id = $1 AND post_id = $2`
+	return database.DB.QueryRow(query, like.UserID, like.PostID).Scan(&like.ID)
+}
+
+func (s *LikeService) upsertReaction(userID, postID, reaction string) error {
+	query := `INSERT INTO likes (id, user_id, post_id, reaction, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, post_id) DO UPDATE SET reaction = EXCLUDED.reaction`
+	_, err := database.DB.Exec(query, uuid.New().String(), userID, postID, reaction, time.Now().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LikeService) GetReactionsByPostID(viewerID, postID string, limit, offset int) (*models.ReactionSummary, error) {
+	if err := checkPostVisible(viewerID, postID); err != nil {
+		return nil, err
+	}
+
+	summary := &models.ReactionSummary{
+		PostID:   postID,
+		Counts:   make(map[string]int),
+		Reactors: []models.Like{},
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	countQuery := `SELECT reaction, COUNT(*) FROM likes WHERE post_id = $1 GROUP BY reaction`
+	countRows, err := database.DB.Query(countQuery, postID)
 	if err != nil {
 		return nil, err
 	}
-	if !postExists {
-		return nil, errors.New("post not found")
+	defer countRows.Close()
+
+	for countRows.Next() {
+		var reaction string
+		var count int
+		if err := countRows.Scan(&reaction, &count); err != nil {
+			return nil, err
+		}
+		summary.Counts[reaction] = count
+		summary.Total += count
+	}
+
+	query := `SELECT id, user_id, post_id, reaction FROM likes WHERE post_id = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3`
+	rows, err := database.DB.Query(query, postID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var like models.Like
+		err := rows.Scan(&like.ID, &like.UserID, &like.PostID, &like.Reaction)
+		if err != nil {
+			return nil, err
+		}
+		summary.Reactors = append(summary.Reactors, like)
+	}
+
+	return summary, nil
+}
+
+func isAllowedReaction(reaction string) bool {
+	allowed := []string{DefaultReaction}
+	if config.AppConfig != nil && len(config.AppConfig.ReactionTypes) > 0 {
+		allowed = config.AppConfig.ReactionTypes
+	}
+
+	for _, r := range allowed {
+		if r == reaction {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteLike removes the like if present. A reaction of another type is
+// left in place. Deleting a like that does not exist is not an error so
+// that clients can retry safely.
+func (s *LikeService) DeleteLike(userID, postID string) error {
+	query := `DELETE FROM likes WHERE user_id = $1 AND post_id = $2 AND reaction = $3`
+	_, err := database.DB.Exec(query, userID, postID, DefaultReaction)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteReaction removes the user's reaction to postID, whatever its type.
+// Like DeleteLike, a missing reaction is not an error.
+func (s *LikeService) DeleteReaction(userID, postID string) error {
+	query := `DELETE FROM likes WHERE user_id = $1 AND post_id = $2`
+	_, err := database.DB.Exec(query, userID, postID)
+	if err != nil {
+		return err
 	}
 
-	query := `SELECT id, user_id, post_id FROM likes WHERE post_id = $1`
+	return nil
+}
+
+func (s *LikeService) GetLikesByPostID(viewerID, postID string) ([]models.Like, error) {
+	if err := checkPostVisible(viewerID, postID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id, user_id, post_id, reaction FROM likes WHERE post_id = $1 AND reaction = 'like'`
 	rows, err := database.DB.Query(query, postID)
 	if err != nil {
 		return nil, err
@@ -72,7 +231,7 @@ func (s *LikeService) GetLikesByPostID(postID string) ([]models.Like, error) {
 	var likes []models.Like
 	for rows.Next() {
 		var like models.Like
-		err := rows.Scan(&like.ID, &like.UserID, &like.PostID)
+		err := rows.Scan(&like.ID, &like.UserID, &like.PostID, &like.Reaction)
 		if err != nil {
 			return nil, err
 		}
@@ -82,19 +241,14 @@ func (s *LikeService) GetLikesByPostID(postID string) ([]models.Like, error) {
 	return likes, nil
 }
 
-func (s *LikeService) GetLikesByUserID(userID string) ([]models.Like, error) {
-	var userExists bool
-	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
-	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
-	if err != nil {
+func (s *LikeService) GetLikesByUserID(viewerID, userID string) ([]models.Like, error) {
+	if err := checkUserVisible(viewerID, userID); err != nil {
 		return nil, err
 	}
-	if !userExists {
-		return nil, errors.New("user not found")
-	}
 
-	query := `SELECT id, user_id, post_id FROM likes WHERE user_id = $1`
-	rows, err := database.DB.Query(query, userID)
+	query := `SELECT l.id, l.user_id, l.post_id, l.reaction FROM likes l JOIN posts p ON p.id = l.post_id
+		WHERE l.user_id = $1 AND l.reaction = 'like' AND ` + visiblePostFilter("p", "$2")
+	rows, err := database.DB.Query(query, userID, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +257,7 @@ func (s *LikeService) GetLikesByUserID(userID string) ([]models.Like, error) {
 	var likes []models.Like
 	for rows.Next() {
 		var like models.Like
-		err := rows.Scan(&like.ID, &like.UserID, &like.PostID)
+		err := rows.Scan(&like.ID, &like.UserID, &like.PostID, &like.Reaction)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"social-media-api/config"
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type MediaService struct{}
+
+func NewMediaService() *MediaService {
+	return &MediaService{}
+}
+
+const (
+	maxImagePixels      = 40_000_000
+	maxPostAttachments  = 4
+	mediaColumns        = `id, user_id, mime_type, size, width, height, created_at`
+	defaultMaxUpload    = 10 << 20
+	defaultThumbnailMax = 320
+)
+
+var allowedMediaTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+func mediaSettings() config.MediaConfig {
+	if config.AppConfig != nil {
+		return config.AppConfig.Media
+	}
+	return config.MediaConfig{Dir: "uploads", MaxUploadBytes: defaultMaxUpload, ThumbnailSize: defaultThumbnailMax}
+}
+
+func MaxUploadBytes() int64 {
+	return mediaSettings().MaxUploadBytes
+}
+
+func scanMedia(row rowScanner) (models.Media, error) {
+	var media models.Media
+	err := row.Scan(&media.ID, &media.UserID, &media.MimeType, &media.Size, &media.Width, &media.Height, &media.CreatedAt)
+	media.URL = "/media/" + media.ID + "/file"
+	media.ThumbnailURL = "/media/" + media.ID + "/thumbnail"
+	return media, err
+}
+
+// Upload validates an image, re-encodes it to drop EXIF and other metadata,
+// stores it together with a thumbnail and records it for the user.
+func (s *MediaService) Upload(userID string, file io.Reader) (*models.Media, error) {
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
+	if err != nil {
+		return nil, err
+	}
+	if !userExists {
+		return nil, errors.New("user not found")
+	}
+
+	settings := mediaSettings()
+	data, err := io.ReadAll(io.LimitReader(file, settings.MaxUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > settings.MaxUploadBytes {
+		return nil, errors.New("file is too large")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("file is required")
+	}
+
+	mimeType := http.DetectContentType(data)
+	extension, ok := allowedMediaTypes[mimeType]
+	if !ok {
+		return nil, errors.New("unsupported media type")
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, errors.New("image dimensions are too large")
+	}
+	if mimeType == "image/gif" {
+		// Every frame is decoded into memory, so a small file with many
+		// highly compressible frames must be rejected before decoding.
+		frames, pixels, err := gifFrameArea(data)
+		if err != nil {
+			return nil, err
+		}
+		if frames > maxGIFFrames || pixels > maxImagePixels {
+			return nil, errors.New("image dimensions are too large")
+		}
+	}
+
+	width, height := cfg.Width, cfg.Height
+	if mimeType == "image/jpeg" && jpegOrientation(data) >= 5 {
+		width, height = height, width
+	}
+
+	cleaned, preview, err := reencodeImage(mimeType, data, settings.ThumbnailSize)
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+
+	media := models.Media{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		MimeType:  mimeType,
+		Size:      int64(len(cleaned)),
+		Width:     width,
+		Height:    height,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	storageKey := media.ID[:2] + "/" + media.ID + "." + extension
+	thumbnailKey := media.ID[:2] + "/" + media.ID + "_thumb.png"
+
+	store := getBlobStore()
+	if err := store.Put(storageKey, bytes.NewReader(cleaned)); err != nil {
+		return nil, err
+	}
+	if err := store.Put(thumbnailKey, bytes.NewReader(preview)); err != nil {
+		store.Delete(storageKey)
+		return nil, err
+	}
+
+	query := `INSERT INTO media (id, user_id, mime_type, size, width, height, storage_key, thumbnail_key, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = database.DB.Exec(query, media.ID, media.UserID, media.MimeType, media.Size, media.Width, media.Height,
+		storageKey, thumbnailKey, media.CreatedAt)
+	if err != nil {
+		store.Delete(storageKey)
+		store.Delete(thumbnailKey)
+		return nil, err
+	}
+
+	media.URL = "/media/" + media.ID + "/file"
+	media.ThumbnailURL = "/media/" + media.ID + "/thumbnail"
+	return &media, nil
+}
+
+func (s *MediaService) GetMediaByID(id string) (*models.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = $1`
+	media, err := scanMedia(database.DB.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("media not found")
+		}
+		return nil, err
+	}
+
+	return &media, nil
+}
+
+// OpenMedia returns the stored file, or its thumbnail, along with the MIME
+// type to serve it with.
+func (s *MediaService) OpenMedia(id string, thumbnail bool) (io.ReadCloser, string, error) {
+	var mimeType, storageKey, thumbnailKey string
+	query := `SELECT mime_type, storage_key, thumbnail_key FROM media WHERE id = $1`
+	err := database.DB.QueryRow(query, id).Scan(&mimeType, &storageKey, &thumbnailKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", errors.New("media not found")
+		}
+		return nil, "", err
+	}
+
+	key := storageKey
+	if thumbnail {
+		key, mimeType = thumbnailKey, "image/png"
+	}
+
+	file, err := getBlobStore().Get(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, mimeType, nil
+}
+
+func reencodeImage(mimeType string, data []byte, thumbnailSize int) ([]byte, []byte, error) {
+	var cleaned bytes.Buffer
+	var first image.Image
+
+	switch mimeType {
+	case "image/gif":
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := gif.EncodeAll(&cleaned, animation); err != nil {
+			return nil, nil, err
+		}
+		first = animation.Image[0]
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := png.Encode(&cleaned, img); err != nil {
+			return nil, nil, err
+		}
+		first = img
+	default:
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		img = applyOrientation(img, jpegOrientation(data))
+		if err := jpeg.Encode(&cleaned, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, nil, err
+		}
+		first = img
+	}
+
+	var preview bytes.Buffer
+	if err := png.Encode(&preview, resizeToFit(first, thumbnailSize)); err != nil {
+		return nil, nil, err
+	}
+
+	return cleaned.Bytes(), preview.Bytes(), nil
+}
+
+// resizeToFit scales src down to fit a maxSize square using a box filter,
+// averaging every source pixel that falls into each destination pixel.
+func resizeToFit(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if maxSize <= 0 || (width <= maxSize && height <= maxSize) {
+		return src
+	}
+
+	scale := float64(maxSize) / float64(max(width, height))
+	dstWidth := max(1, int(float64(width)*scale))
+	dstHeight := max(1, int(float64(height)*scale))
+	dst := image.NewRGBA64(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0 := bounds.Min.Y + y*height/dstHeight
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*height/dstHeight)
+		for x := 0; x < dstWidth; x++ {
+			x0 := bounds.Min.X + x*width/dstWidth
+			x1 := max(x0+1, bounds.Min.X+(x+1)*width/dstWidth)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+
+	return dst
+}
+
+// attachMediaToPost links uploaded media to a post in the given order. Media
+// must exist and belong to the post's author.
+func attachMediaToPost(db execer, postID, userID string, attachments []models.Media) ([]models.Media, error) {
+	if len(attachments) == 0 {
+		return []models.Media{}, nil
+	}
+	if len(attachments) > maxPostAttachments {
+		return nil, errors.New("too many attachments")
+	}
+
+	ids := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		ids[i] = attachment.ID
+	}
+	if len(uniqueIDs(ids)) != len(ids) {
+		return nil, errors.New("duplicate attachment")
+	}
+
+	owned, err := loadMediaByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make([]models.Media, len(ids))
+	query := `INSERT INTO post_attachments (post_id, media_id, position) VALUES ($1, $2, $3)`
+	for i, id := range ids {
+		media, ok := owned[id]
+		if !ok || media.UserID != userID {
+			return nil, errors.New("media not found")
+		}
+		if _, err := db.Exec(query, postID, id, i); err != nil {
+			return nil, err
+		}
+		linked[i] = media
+	}
+
+	return linked, nil
+}
+
+func loadMediaByIDs(ids []string) (map[string]models.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = ANY($1)`
+	rows, err := database.DB.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	media := make(map[string]models.Media)
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media[item.ID] = item
+	}
+
+	return media, rows.Err()
+}
+
+func attachPostMedia(posts []models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	query := `SELECT pa.post_id, m.id, m.user_id, m.mime_type, m.size, m.width, m.height, m.created_at
+		FROM post_attachments pa JOIN media m ON m.id = pa.media_id
+		WHERE pa.post_id = ANY($1)
+		ORDER BY pa.position`
+	rows, err := database.DB.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	attachments := make(map[string][]models.Media)
+	for rows.Next() {
+		var postID string
+		var media models.Media
+		err := rows.Scan(&postID, &media.ID, &media.UserID, &media.MimeType, &media.Size, &media.Width, &media.Height, &media.CreatedAt)
+		if err != nil {
+			return err
+		}
+		media.URL = "/media/" + media.ID + "/file"
+		media.ThumbnailURL = "/media/" + media.ID + "/thumbnail"
+		attachments[postID] = append(attachments[postID], media)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range posts {
+		posts[i].Attachments = attachments[posts[i].ID]
+		if posts[i].Attachments == nil {
+			posts[i].Attachments = []models.Media{}
+		}
+	}
+
+	return nil
+}
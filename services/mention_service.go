@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"social-media-api/database"
+	"social-media-api/models"
+	"social-media-api/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type MentionService struct{}
+
+func NewMentionService() *MentionService {
+	return &MentionService{}
+}
+
+// resolveMentions matches @username tokens against existing users. Tokens
+// that do not name a user, or name one who has blocked the author, are
+// dropped and stay plain text.
+func resolveMentions(authorID, content string) ([]models.Mention, error) {
+	candidates := utils.ExtractMentions(content)
+	if len(candidates) == 0 {
+		return []models.Mention{}, nil
+	}
+
+	usernames := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		usernames[i] = strings.ToLower(candidate.Username)
+	}
+
+	query := `SELECT id, username FROM users WHERE LOWER(username) = ANY($1)
+		AND NOT EXISTS (SELECT 1 FROM blocks WHERE blocker_id = users.id AND blocked_id = $2)`
+	rows, err := database.DB.Query(query, pq.Array(uniqueIDs(usernames)), authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make(map[string]models.UserSummary)
+	for rows.Next() {
+		var user models.UserSummary
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, err
+		}
+		users[strings.ToLower(user.Username)] = user
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	mentions := []models.Mention{}
+	for _, candidate := range candidates {
+		user, ok := users[strings.ToLower(candidate.Username)]
+		if !ok {
+			continue
+		}
+		mentions = append(mentions, models.Mention{
+			UserID:   user.ID,
+			Username: user.Username,
+			Offset:   candidate.Offset,
+			Length:   candidate.Length,
+		})
+	}
+
+	return mentions, nil
+}
+
+func storeMentions(db execer, postID, commentID string, mentions []models.Mention) error {
+	var postRef, commentRef interface{}
+	if postID != "" {
+		postRef = postID
+	}
+	if commentID != "" {
+		commentRef = commentID
+	}
+
+	query := `INSERT INTO mentions (id, user_id, post_id, comment_id, start_offset, length) VALUES ($1, $2, $3, $4, $5, $6)`
+	for _, mention := range mentions {
+		_, err := db.Exec(query, uuid.New().String(), mention.UserID, postRef, commentRef, mention.Offset, mention.Length)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadMentions returns the mentions stored for the given posts or comments,
+// keyed by their ID. column is either "post_id" or "comment_id".
+func loadMentions(column string, ids []string) (map[string][]models.Mention, error) {
+	mentions := make(map[string][]models.Mention)
+	if len(ids) == 0 {
+		return mentions, nil
+	}
+
+	query := `SELECT m.` + column + `, m.user_id, u.username, m.start_offset, m.length
+		FROM mentions m JOIN users u ON u.id = m.user_id
+		WHERE m.` + column + ` = ANY($1)
+		ORDER BY m.start_offset`
+	rows, err := database.DB.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ownerID string
+		var mention models.Mention
+		if err := rows.Scan(&ownerID, &mention.UserID, &mention.Username, &mention.Offset, &mention.Length); err != nil {
+			return nil, err
+		}
+		mentions[ownerID] = append(mentions[ownerID], mention)
+	}
+
+	return mentions, rows.Err()
+}
+
+func attachPostMentions(posts []models.Post) error {
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	mentions, err := loadMentions("post_id", ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		posts[i].Mentions = mentions[posts[i].ID]
+		if posts[i].Mentions == nil {
+			posts[i].Mentions = []models.Mention{}
+		}
+	}
+
+	return nil
+}
+
+func attachCommentMentions(comments []models.Comment) error {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	mentions, err := loadMentions("comment_id", ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].Mentions = mentions[comments[i].ID]
+		if comments[i].Mentions == nil {
+			comments[i].Mentions = []models.Mention{}
+		}
+	}
+
+	return nil
+}
+
+func (s *MentionService) GetPostsMentioningUser(viewerID, userID string, limit, offset int) ([]models.Post, error) {
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
+	if err != nil {
+		return nil, err
+	}
+	if !userExists {
+		return nil, errors.New("user not found")
+	}
+
+	query := `SELECT ` + postColumns + ` FROM posts
+		WHERE id IN (SELECT post_id FROM mentions WHERE user_id = $1 AND post_id IS NOT NULL)
+		AND ` + visiblePostFilter("posts", "$4") + `
+		AND ` + notMutedFilter("user_id", "search_vector", "$4") + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := database.DB.Query(query, userID, limit, offset, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
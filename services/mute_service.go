@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"social-media-api/database"
+	"social-media-api/models"
+	"social-media-api/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type MuteService struct{}
+
+func NewMuteService() *MuteService {
+	return &MuteService{}
+}
+
+const maxMutedWordLength = 100
+
+// parseMuteExpiry validates an optional RFC 3339 expiry, which must lie in
+// the future. Nil means the mute never expires.
+func parseMuteExpiry(expiresAt *string) (interface{}, error) {
+	if expiresAt == nil || *expiresAt == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, *expiresAt)
+	if err != nil || !parsed.After(time.Now()) {
+		return nil, errors.New("invalid expiration")
+	}
+	return parsed.Format(time.RFC3339), nil
+}
+
+// MuteUser hides the muted account's posts and comments from the muting
+// user. Muting an account again replaces the previous expiry.
+func (s *MuteService) MuteUser(mute *models.Mute) error {
+	if mute.UserID == mute.MutedUserID {
+		return errors.New("cannot mute yourself")
+	}
+
+	expiresAt, err := parseMuteExpiry(mute.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	var userExists bool
+	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	err = database.DB.QueryRow(checkUserQuery, mute.MutedUserID).Scan(&userExists)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errors.New("user not found")
+	}
+
+	query := `INSERT INTO mutes (id, user_id, muted_user_id, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, muted_user_id) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		RETURNING id, expires_at, created_at`
+	return database.DB.QueryRow(query, uuid.New().String(), mute.UserID, mute.MutedUserID, expiresAt,
+		time.Now().Format(time.RFC3339)).Scan(&mute.ID, &mute.ExpiresAt, &mute.CreatedAt)
+}
+
+func (s *MuteService) UnmuteUser(userID, mutedUserID string) error {
+	query := `DELETE FROM mutes WHERE user_id = $1 AND muted_user_id = $2`
+	result, err := database.DB.Exec(query, userID, mutedUserID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("mute not found")
+	}
+
+	return nil
+}
+
+// GetMutes lists the accounts userID currently mutes. Expired mutes are
+// left out.
+func (s *MuteService) GetMutes(userID string) ([]models.Mute, error) {
+	query := `SELECT id, user_id, muted_user_id, expires_at, created_at FROM mutes
+		WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC`
+	rows, err := database.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutes := []models.Mute{}
+	for rows.Next() {
+		var mute models.Mute
+		err := rows.Scan(&mute.ID, &mute.UserID, &mute.MutedUserID, &mute.ExpiresAt, &mute.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		mutes = append(mutes, mute)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	mutedIDs := make([]string, len(mutes))
+	for i, mute := range mutes {
+		mutedIDs[i] = mute.MutedUserID
+	}
+	users, err := loadUserSummaries(mutedIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range mutes {
+		mutes[i].MutedUser = users[mutes[i].MutedUserID]
+	}
+
+	return mutes, nil
+}
+
+// MuteWord mutes a word, phrase or #hashtag. Words are matched
+// case-insensitively against whole words in post and comment content.
+func (s *MuteService) MuteWord(word *models.MutedWord) error {
+	word.Word = strings.ToLower(strings.TrimSpace(word.Word))
+	if word.Word == "" {
+		return errors.New("word is required")
+	}
+	if utils.CharLength(word.Word) > maxMutedWordLength {
+		return errors.New("word is too long")
+	}
+	if strings.IndexFunc(word.Word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		return errors.New("invalid muted word")
+	}
+
+	expiresAt, err := parseMuteExpiry(word.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO muted_words (id, user_id, word, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, word) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		RETURNING id, expires_at, created_at`
+	return database.DB.QueryRow(query, uuid.New().String(), word.UserID, word.Word, expiresAt,
+		time.Now().Format(time.RFC3339)).Scan(&word.ID, &word.ExpiresAt, &word.CreatedAt)
+}
+
+func (s *MuteService) UnmuteWord(userID, wordID string) error {
+	query := `DELETE FROM muted_words WHERE user_id = $1 AND id = $2`
+	result, err := database.DB.Exec(query, userID, wordID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("muted word not found")
+	}
+
+	return nil
+}
+
+func (s *MuteService) GetMutedWords(userID string) ([]models.MutedWord, error) {
+	query := `SELECT id, user_id, word, expires_at, created_at FROM muted_words
+		WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC`
+	rows, err := database.DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := []models.MutedWord{}
+	for rows.Next() {
+		var word models.MutedWord
+		err := rows.Scan(&word.ID, &word.UserID, &word.Word, &word.ExpiresAt, &word.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+
+	return words, rows.Err()
+}
+
+// filterMutedPosts drops posts that userID has muted by author or word.
+func filterMutedPosts(userID string, posts []models.Post) ([]models.Post, error) {
+	if userID == "" || len(posts) == 0 {
+		return posts, nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	query := `SELECT id FROM posts WHERE id = ANY($1) AND ` + notMutedFilter("user_id", "search_vector", "$2")
+	kept, err := queryIDSet(query, pq.Array(ids), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]models.Post, 0, len(posts))
+	for _, post := range posts {
+		if kept[post.ID] {
+			filtered = append(filtered, post)
+		}
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"time"
+
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/google/uuid"
+)
+
+type NotificationService struct{}
+
+const MentionNotification = "mention"
+
+func NewNotificationService() *NotificationService {
+	return &NotificationService{}
+}
+
+// notifyMentions records a mention notification for every user mentioned
+// in a post or comment, once per user. commentID is empty for posts; postID
+// is always the post the mention appears in. Authors are not notified of
+// their own mentions.
+func notifyMentions(db execer, actorID, postID, commentID string, mentions []models.Mention) error {
+	var commentRef interface{}
+	if commentID != "" {
+		commentRef = commentID
+	}
+
+	notified := make(map[string]bool)
+	query := `INSERT INTO notifications (id, user_id, actor_id, type, post_id, comment_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, mention := range mentions {
+		if mention.UserID == actorID || notified[mention.UserID] {
+			continue
+		}
+		notified[mention.UserID] = true
+
+		_, err := db.Exec(query, uuid.New().String(), mention.UserID, actorID, MentionNotification, postID, commentRef,
+			time.Now().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetNotifications lists userID's notifications, newest first. A notification
+// only appears while its post is published and visible to the user, and is
+// hidden while the actor is blocked or muted. Mentions in drafts and scheduled
+// posts therefore show up once the post is published, dated to that moment.
+func (s *NotificationService) GetNotifications(userID string, limit, offset int) ([]models.Notification, error) {
+	query := `SELECT n.id, n.user_id, n.actor_id, n.type, n.post_id, n.comment_id, n.read_at IS NOT NULL,
+			GREATEST(n.created_at, p.created_at) AS notified_at
+		FROM notifications n
+		JOIN posts p ON p.id = n.post_id
+		LEFT JOIN comments c ON c.id = n.comment_id
+		WHERE n.user_id = $1
+		AND ` + visiblePostFilter("p", "$1") + `
+		AND ` + notBlockedFilter("n.actor_id", "$1") + `
+		AND ` + notMutedFilter("n.actor_id", "to_tsvector('simple', COALESCE(c.content, p.content))", "$1") + `
+		ORDER BY notified_at DESC, n.id DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := database.DB.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		err := rows.Scan(&notification.ID, &notification.UserID, &notification.ActorID, &notification.Type,
+			&notification.PostID, &notification.CommentID, &notification.Read, &notification.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	actorIDs := make([]string, len(notifications))
+	for i, notification := range notifications {
+		actorIDs[i] = notification.ActorID
+	}
+	actors, err := loadUserSummaries(actorIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range notifications {
+		notifications[i].Actor = actors[notifications[i].ActorID]
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationsRead marks all of userID's unread notifications as read.
+func (s *NotificationService) MarkNotificationsRead(userID string) error {
+	query := `UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL`
+	_, err := database.DB.Exec(query, userID, time.Now().Format(time.RFC3339))
+	return err
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"log"
+	"time"
+
+	"social-media-api/config"
+)
+
+func schedulerSettings() config.SchedulerConfig {
+	if config.AppConfig != nil {
+		return config.AppConfig.Scheduler
+	}
+	return config.SchedulerConfig{IntervalSeconds: 30, BatchSize: 100}
+}
+
+// StartPostScheduler publishes due scheduled posts immediately and then on
+// every scheduler interval for the lifetime of the process. The schedule
+// lives in the posts table, so posts that came due while no instance was
+// running are published on the next start.
+func StartPostScheduler() {
+	settings := schedulerSettings()
+	interval := time.Duration(settings.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := PublishDuePosts(); err != nil {
+				log.Printf("Failed to publish scheduled posts: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// PublishDuePosts publishes every scheduled post whose publish time has
+// passed and returns how many it published. Each batch is claimed with
+// FOR UPDATE SKIP LOCKED inside a single UPDATE, so several instances can
+// run the scheduler at once without publishing a post twice.
+func PublishDuePosts() (int, error) {
+	batchSize := schedulerSettings().BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	query := `UPDATE posts SET status = 'published', publish_at = NULL, created_at = $2
+		WHERE id IN (
+			SELECT id FROM posts WHERE status = 'scheduled' AND publish_at <= $2
+			ORDER BY publish_at LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id`
+
+	published := 0
+	for {
+		ids, err := queryIDList(query, batchSize, time.Now().Format(time.RFC3339))
+		if err != nil {
+			return published, err
+		}
+
+		for _, id := range ids {
+			fanOutPost(id)
+		}
+		published += len(ids)
+
+		if len(ids) < batchSize {
+			return published, nil
+		}
+	}
+}
@@ -8,12 +8,51 @@ import (
 
 	"social-media-api/database"
 	"social-media-api/models"
+	"social-media-api/utils"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type PostService struct{}
 
+const postColumns = `id, user_id, content, visibility, status, publish_at, repost_of_id, quote_of_id, is_quote,
+	in_reply_to_id, conversation_id, created_at, like_count, comment_count`
+
+func postFields(post *models.Post) []interface{} {
+	return []interface{}{&post.ID, &post.UserID, &post.Content, &post.Visibility, &post.Status, &post.PublishAt,
+		&post.RepostOfID, &post.QuoteOfID, &post.IsQuote, &post.InReplyToID, &post.ConversationID,
+		&post.CreatedAt, &post.LikeCount, &post.CommentCount}
+}
+
+// postVisibilities are the audiences a post can be shared with: everyone,
+// the author's followers, only the users it mentions, or only the author.
+var postVisibilities = map[string]bool{"public": true, "followers": true, "mentioned": true, "private": true}
+
+// postStatuses are the states a post can be created in. Drafts and scheduled
+// posts are only visible to their author until they are published.
+var postStatuses = map[string]bool{"published": true, "draft": true, "scheduled": true}
+
+// parsePublishAt validates the RFC 3339 publish time of a scheduled post,
+// which must lie in the future.
+func parsePublishAt(publishAt *string) (string, error) {
+	if publishAt == nil || *publishAt == "" {
+		return "", errors.New("publish_at is required")
+	}
+
+	parsed, err := time.Parse(time.RFC3339, *publishAt)
+	if err != nil || !parsed.After(time.Now()) {
+		return "", errors.New("invalid publish_at")
+	}
+	return parsed.Format(time.RFC3339), nil
+}
+
+func scanPost(row rowScanner) (models.Post, error) {
+	var post models.Post
+	err := row.Scan(postFields(&post)...)
+	return post, err
+}
+
 func NewPostService() *PostService {
 	return &PostService{}
 }
@@ -22,6 +61,30 @@ func (s *PostService) CreatePost(post *models.Post) error {
 	if post.Content == "" {
 		return errors.New("content tidak boleh kosong")
 	}
+	if post.Visibility == "" {
+		post.Visibility = "public"
+	}
+	if !postVisibilities[post.Visibility] {
+		return errors.New("invalid visibility")
+	}
+	if post.Status == "" {
+		post.Status = "published"
+	}
+	if !postStatuses[post.Status] {
+		return errors.New("invalid status")
+	}
+
+	var publishAt interface{}
+	if post.Status == "scheduled" {
+		parsed, err := parsePublishAt(post.PublishAt)
+		if err != nil {
+			return err
+		}
+		post.PublishAt = &parsed
+		publishAt = parsed
+	} else {
+		post.PublishAt = nil
+	}
 
 	var userExists bool
 	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
@@ -33,21 +96,92 @@ func (s *PostService) CreatePost(post *models.Post) error {
 		return errors.New("user harus valid")
 	}
 
+	post.RepostOfID = nil
+	post.IsQuote = post.QuoteOfID != nil && *post.QuoteOfID != ""
+	if post.IsQuote {
+		quotedID, err := resolveShareTarget(post.UserID, *post.QuoteOfID)
+		if err != nil {
+			if err.Error() == "post not found" {
+				return errors.New("quoted post not found")
+			}
+			return err
+		}
+		post.QuoteOfID = &quotedID
+	} else {
+		post.QuoteOfID = nil
+	}
+
 	post.ID = uuid.New().String()
 	post.CreatedAt = time.Now().Format(time.RFC3339)
+	post.ConversationID = post.ID
+	if post.InReplyToID != nil && *post.InReplyToID != "" {
+		parent, err := resolveReplyParent(post.UserID, *post.InReplyToID)
+		if err != nil {
+			return err
+		}
+		post.InReplyToID = &parent.ID
+		post.ConversationID = parent.ConversationID
+	} else {
+		post.InReplyToID = nil
+	}
+
+	mentions, err := resolveMentions(post.UserID, post.Content)
+	if err != nil {
+		return err
+	}
 
-	query := `INSERT INTO posts (id, user_id, content, created_at) VALUES ($1, $2, $3, $4)`
-	_, err = database.DB.Exec(query, post.ID, post.UserID, post.Content, post.CreatedAt)
+	tx, err := database.DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
+	query := `INSERT INTO posts (id, user_id, content, visibility, status, publish_at, quote_of_id, is_quote,
+			in_reply_to_id, conversation_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err = tx.Exec(query, post.ID, post.UserID, post.Content, post.Visibility, post.Status, publishAt,
+		post.QuoteOfID, post.IsQuote, post.InReplyToID, post.ConversationID, post.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := indexPostHashtags(tx, post.ID, post.Content); err != nil {
+		return err
+	}
+
+	if err := storeMentions(tx, post.ID, "", mentions); err != nil {
+		return err
+	}
+
+	if err := notifyMentions(tx, post.UserID, post.ID, "", mentions); err != nil {
+		return err
+	}
+
+	attachments, err := attachMediaToPost(tx, post.ID, post.UserID, post.Attachments)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	post.Hashtags = utils.ExtractHashtags(post.Content)
+	if post.Hashtags == nil {
+		post.Hashtags = []string{}
+	}
+	post.Mentions = mentions
+	post.Attachments = attachments
+
+	if post.Status == "published" {
+		go fanOutPost(post.ID)
+	}
 	return nil
 }
 
-func (s *PostService) GetAllPosts() ([]models.Post, error) {
-	query := `SELECT id, user_id, content, created_at FROM posts ORDER BY created_at DESC`
-	rows, err := database.DB.Query(query)
+func (s *PostService) GetAllPosts(viewerID string) ([]models.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE ` + visiblePostFilter("posts", "$1") + ` ORDER BY created_at DESC`
+	rows, err := database.DB.Query(query, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -55,8 +189,7 @@ func (s *PostService) GetAllPosts() ([]models.Post, error) {
 
 	var posts []models.Post
 	for rows.Next() {
-		var post models.Post
-		err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt)
+		post, err := scanPost(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -66,30 +199,29 @@ func (s *PostService) GetAllPosts() ([]models.Post, error) {
 	return posts, nil
 }
 
-func (s *PostService) GetPostsWithFilters(userID, keyword string) ([]models.Post, error) {
+func (s *PostService) GetPostsWithFilters(viewerID, userID, keyword string) ([]models.Post, error) {
 	var query string
-	var args []interface{}
+	args := []interface{}{viewerID}
 
-	baseQuery := `SELECT id, user_id, content, created_at FROM posts WHERE 1=1`
+	baseQuery := `SELECT ` + postColumns + ` FROM posts WHERE ` + visiblePostFilter("posts", "$1")
 
 	if userID != "" {
-		var userExists bool
-		checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
-		err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
-		if err != nil {
+		if err := checkUserVisible(viewerID, userID); err != nil {
 			return nil, err
 		}
-		if !userExists {
-			return nil, errors.New("user not found")
-		}
 
 		baseQuery += ` AND user_id = $` + fmt.Sprintf("%d", len(args)+1)
 		args = append(args, userID)
 	}
 
 	if keyword != "" {
-		baseQuery += ` AND content ILIKE $` + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, "%"+keyword+"%")
+		tsQuery, err := utils.BuildTSQuery(keyword)
+		if err != nil {
+			return nil, err
+		}
+
+		baseQuery += ` AND search_vector @@ to_tsquery('simple', $` + fmt.Sprintf("%d", len(args)+1) + `)`
+		args = append(args, tsQuery)
 	}
 
 	query = baseQuery + ` ORDER BY created_at DESC`
@@ -102,8 +234,7 @@ func (s *PostService) GetPostsWithFilters(userID, keyword string) ([]models.Post
 
 	var posts []models.Post
 	for rows.Next() {
-		var post models.Post
-		err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt)
+		post, err := scanPost(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -113,10 +244,11 @@ func (s *PostService) GetPostsWithFilters(userID, keyword string) ([]models.Post
 	return posts, nil
 }
 
-func (s *PostService) GetPostByID(id string) (*models.Post, error) {
-	var post models.Post
-	query := `SELECT id, user_id, content, created_at FROM posts WHERE id = $1`
-	err := database.DB.QueryRow(query, id).Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt)
+// GetPostByID also returns the viewer's own drafts and scheduled posts,
+// which every other read path leaves out.
+func (s *PostService) GetPostByID(viewerID, id string) (*models.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = $1 AND (user_id = $2 OR ` + visiblePostFilter("posts", "$2") + `)`
+	post, err := scanPost(database.DB.QueryRow(query, id, viewerID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("post not found")
@@ -127,19 +259,14 @@ func (s *PostService) GetPostByID(id string) (*models.Post, error) {
 	return &post, nil
 }
 
-func (s *PostService) GetPostsByUserID(userID string) ([]models.Post, error) {
-	var userExists bool
-	checkUserQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
-	err := database.DB.QueryRow(checkUserQuery, userID).Scan(&userExists)
-	if err != nil {
+func (s *PostService) GetPostsByUserID(viewerID, userID string) ([]models.Post, error) {
+	if err := checkUserVisible(viewerID, userID); err != nil {
 		return nil, err
 	}
-	if !userExists {
-		return nil, errors.New("user not found")
-	}
 
-	query := `SELECT id, user_id, content, created_at FROM posts WHERE user_id = $1 ORDER BY created_at DESC`
-	rows, err := database.DB.Query(query, userID)
+	query := `SELECT ` + postColumns + ` FROM posts WHERE user_id = $1 AND ` + visiblePostFilter("posts", "$2") + `
+		ORDER BY created_at DESC`
+	rows, err := database.DB.Query(query, userID, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -147,8 +274,7 @@ func (s *PostService) GetPostsByUserID(userID string) ([]models.Post, error) {
 
 	var posts []models.Post
 	for rows.Next() {
-		var post models.Post
-		err := rows.Scan(&post.ID, &post.UserID, &post.Content, &post.CreatedAt)
+		post, err := scanPost(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -158,16 +284,73 @@ func (s *PostService) GetPostsByUserID(userID string) ([]models.Post, error) {
 	return posts, nil
 }
 
+func (s *PostService) GetDrafts(userID string) ([]models.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE user_id = $1 AND status = 'draft' ORDER BY created_at DESC`
+	return queryPosts(query, userID)
+}
+
+func (s *PostService) GetScheduledPosts(userID string) ([]models.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE user_id = $1 AND status = 'scheduled' ORDER BY publish_at, id`
+	return queryPosts(query, userID)
+}
+
+func queryPosts(query string, args ...interface{}) ([]models.Post, error) {
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []models.Post{}
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+// PublishPost publishes one of the author's drafts or scheduled posts right
+// away. Like the scheduler, it moves created_at to the publish time so the
+// post shows up at the top of timelines.
+func (s *PostService) PublishPost(userID, id string) (*models.Post, error) {
+	query := `UPDATE posts SET status = 'published', publish_at = NULL, created_at = $3
+		WHERE id = $1 AND user_id = $2 AND status <> 'published'
+		RETURNING ` + postColumns
+	post, err := scanPost(database.DB.QueryRow(query, id, userID, time.Now().Format(time.RFC3339)))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		var status string
+		checkQuery := `SELECT status FROM posts WHERE id = $1 AND user_id = $2`
+		if err := database.DB.QueryRow(checkQuery, id, userID).Scan(&status); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.New("post not found")
+			}
+			return nil, err
+		}
+		return nil, errors.New("post already published")
+	}
+
+	go fanOutPost(post.ID)
+	return &post, nil
+}
+
 func (s *PostService) DeletePost(id string) error {
-	var postExists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)`
-	err := database.DB.QueryRow(checkQuery, id).Scan(&postExists)
+	var authorID string
+	checkQuery := `SELECT user_id FROM posts WHERE id = $1`
+	err := database.DB.QueryRow(checkQuery, id).Scan(&authorID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("post not found")
+		}
 		return err
 	}
-	if !postExists {
-		return errors.New("post not found")
-	}
 
 	query := `DELETE FROM posts WHERE id = $1`
 	_, err = database.DB.Exec(query, id)
@@ -175,5 +358,52 @@ func (s *PostService) DeletePost(id string) error {
 		return err
 	}
 
+	go removePostFromTimelines(authorID, id)
+	return nil
+}
+
+// PreparePosts fills in everything a post response carries beyond its own
+// row: hashtags, mentions, attachments, viewer-relative fields, reposted or
+// quoted originals and any requested expansions.
+func (s *PostService) PreparePosts(viewerID string, posts []models.Post, includes map[string]bool) error {
+	if err := attachHashtags(posts); err != nil {
+		return err
+	}
+	if err := attachPostMentions(posts); err != nil {
+		return err
+	}
+	if err := attachPostMedia(posts); err != nil {
+		return err
+	}
+	if err := s.AnnotatePostsForViewer(viewerID, posts); err != nil {
+		return err
+	}
+	if err := s.attachSharedPosts(viewerID, posts); err != nil {
+		return err
+	}
+	return s.ExpandPosts(posts, includes)
+}
+
+func (s *PostService) AnnotatePostsForViewer(viewerID string, posts []models.Post) error {
+	if viewerID == "" || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	query := `SELECT post_id FROM likes WHERE user_id = $1 AND reaction = 'like' AND post_id = ANY($2)`
+	liked, err := queryIDSet(query, viewerID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		likedByMe := liked[posts[i].ID]
+		posts[i].LikedByMe = &likedByMe
+	}
+
 	return nil
 }
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/lib/pq"
+)
+
+// visibleAuthorFilter returns a SQL condition that keeps rows whose author,
+// in authorColumn, is visible to the viewer bound to viewerParam: the viewer
+// themselves, and public accounts or private accounts the viewer follows as
+// long as neither has blocked the other. An empty viewer ID only sees public
+// accounts.
+func visibleAuthorFilter(authorColumn, viewerParam string) string {
+	return `(` + authorColumn + ` = ` + viewerParam + ` OR (NOT EXISTS (
+		SELECT 1 FROM users vu WHERE vu.id = ` + authorColumn + ` AND vu.is_private
+		AND NOT EXISTS (SELECT 1 FROM follows vf WHERE vf.follower_id = ` + viewerParam + ` AND vf.following_id = vu.id))
+		AND ` + notBlockedFilter(authorColumn, viewerParam) + `))`
+}
+
+// visiblePostFilter returns a SQL condition that keeps published posts, from
+// the table or alias postTable, the viewer bound to viewerParam may see.
+// Authors always see their own posts; anyone else needs the author to be
+// visible and the post's visibility to include them. Reposts are only kept
+// while the viewer may also see the original.
+func visiblePostFilter(postTable, viewerParam string) string {
+	return `(` + ownPostFilter(postTable, viewerParam) + ` AND (` + postTable + `.repost_of_id IS NULL OR EXISTS (
+		SELECT 1 FROM posts ro WHERE ro.id = ` + postTable + `.repost_of_id AND ` + ownPostFilter("ro", viewerParam) + `)))`
+}
+
+// ownPostFilter is visiblePostFilter without the check on reposted originals.
+func ownPostFilter(postTable, viewerParam string) string {
+	authorColumn := postTable + `.user_id`
+	return `(` + postTable + `.status = 'published' AND (` + authorColumn + ` = ` + viewerParam + ` OR (` + visibleAuthorFilter(authorColumn, viewerParam) + ` AND (
+		` + postTable + `.visibility = 'public'
+		OR (` + postTable + `.visibility = 'followers' AND EXISTS (
+			SELECT 1 FROM follows pf WHERE pf.follower_id = ` + viewerParam + ` AND pf.following_id = ` + authorColumn + `))
+		OR (` + postTable + `.visibility = 'mentioned' AND EXISTS (
+			SELECT 1 FROM mentions pm WHERE pm.post_id = ` + postTable + `.id AND pm.user_id = ` + viewerParam + `))))))`
+}
+
+// blockedFilter matches rows whose user, in userColumn, has a block in
+// either direction with the viewer bound to viewerParam.
+func blockedFilter(userColumn, viewerParam string) string {
+	return `EXISTS (SELECT 1 FROM blocks vb
+		WHERE (vb.blocker_id = ` + viewerParam + ` AND vb.blocked_id = ` + userColumn + `)
+			OR (vb.blocker_id = ` + userColumn + ` AND vb.blocked_id = ` + viewerParam + `))`
+}
+
+func notBlockedFilter(userColumn, viewerParam string) string {
+	return `NOT ` + blockedFilter(userColumn, viewerParam)
+}
+
+// notMutedFilter keeps rows the viewer bound to viewerParam has not muted,
+// either by their author or by a muted word or phrase matching textVector,
+// a tsvector expression over the row's content. Expired mutes are ignored.
+func notMutedFilter(authorColumn, textVector, viewerParam string) string {
+	return `NOT EXISTS (SELECT 1 FROM mutes vm
+			WHERE vm.user_id = ` + viewerParam + ` AND vm.muted_user_id = ` + authorColumn + `
+			AND (vm.expires_at IS NULL OR vm.expires_at > NOW()))
+		AND NOT EXISTS (SELECT 1 FROM muted_words vw
+			WHERE vw.user_id = ` + viewerParam + ` AND (vw.expires_at IS NULL OR vw.expires_at > NOW())
+			AND ` + textVector + ` @@ phraseto_tsquery('simple', vw.word))`
+}
+
+// checkUserVisible fails with "this account is private" or "user is blocked"
+// when the viewer may not see the posts, likes and connections of userID.
+func checkUserVisible(viewerID, userID string) error {
+	var visible, blocked bool
+	query := `SELECT ` + visibleAuthorFilter("id", "$2") + `, ` + blockedFilter("id", "$2") + ` FROM users WHERE id = $1`
+	err := database.DB.QueryRow(query, userID, viewerID).Scan(&visible, &blocked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	if blocked && userID != viewerID {
+		return errors.New("user is blocked")
+	}
+	if !visible {
+		return errors.New("this account is private")
+	}
+
+	return nil
+}
+
+// isBlockedBetween reports whether either user has blocked the other.
+func isBlockedBetween(userID, otherID string) (bool, error) {
+	var blocked bool
+	query := `SELECT ` + blockedFilter("$2::varchar", "$1::varchar")
+	err := database.DB.QueryRow(query, userID, otherID).Scan(&blocked)
+	return blocked, err
+}
+
+func (s *UserService) CheckUserVisible(viewerID, userID string) error {
+	return checkUserVisible(viewerID, userID)
+}
+
+// checkPostVisible reports posts the viewer may not see as missing, so their
+// existence is not revealed.
+func checkPostVisible(viewerID, postID string) error {
+	var visible bool
+	query := `SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1 AND ` + visiblePostFilter("posts", "$2") + `)`
+	err := database.DB.QueryRow(query, postID, viewerID).Scan(&visible)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return errors.New("post not found")
+	}
+
+	return nil
+}
+
+// FilterVisiblePosts drops posts the viewer may not see. It is for lists
+// assembled outside a single query, such as timelines served from the store.
+func (s *PostService) FilterVisiblePosts(viewerID string, posts []models.Post) ([]models.Post, error) {
+	if len(posts) == 0 {
+		return posts, nil
+	}
+
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+
+	query := `SELECT id FROM posts WHERE id = ANY($1) AND ` + visiblePostFilter("posts", "$2")
+	visible, err := queryIDSet(query, pq.Array(ids), viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]models.Post, 0, len(posts))
+	for _, post := range posts {
+		if visible[post.ID] {
+			filtered = append(filtered, post)
+		}
+	}
+
+	return filtered, nil
+}
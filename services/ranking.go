@@ -0,0 +1,131 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"social-media-api/config"
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/lib/pq"
+)
+
+type RankingFeatures struct {
+	AgeHours     float64
+	LikeCount    int
+	CommentCount int
+	Affinity     int
+}
+
+// ScorePost combines recency decay, engagement and the viewer's affinity
+// with the author. Engagement terms are log-scaled so a handful of very
+// popular posts cannot drown out everything else.
+func ScorePost(features RankingFeatures, weights config.RankingConfig) float64 {
+	age := math.Max(features.AgeHours, 0)
+
+	recency := 1.0
+	if weights.HalfLifeHours > 0 {
+		recency = math.Exp(-math.Ln2 * age / weights.HalfLifeHours)
+	}
+
+	return weights.RecencyWeight*recency +
+		weights.LikeWeight*math.Log1p(float64(features.LikeCount)) +
+		weights.CommentWeight*math.Log1p(float64(features.CommentCount)) +
+		weights.AffinityWeight*math.Log1p(float64(features.Affinity))
+}
+
+// RankPosts orders posts by descending score, breaking ties by recency so
+// the result is deterministic for a given now. Ages are measured from now to
+// the instant each post was created.
+func RankPosts(posts []models.Post, affinity map[string]int, weights config.RankingConfig, now time.Time) []models.Post {
+	type scoredPost struct {
+		post  models.Post
+		entry TimelineEntry
+		score float64
+	}
+
+	scored := make([]scoredPost, 0, len(posts))
+	for _, post := range posts {
+		entry, err := timelineEntry(post)
+		if err != nil {
+			continue
+		}
+		features := RankingFeatures{
+			AgeHours:     now.Sub(storedTime(entry.CreatedAt)).Hours(),
+			LikeCount:    post.LikeCount,
+			CommentCount: post.CommentCount,
+			Affinity:     affinity[post.UserID],
+		}
+		scored = append(scored, scoredPost{post: post, entry: entry, score: ScorePost(features, weights)})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return newerEntry(scored[i].entry, scored[j].entry)
+	})
+
+	ranked := make([]models.Post, len(scored))
+	for i, s := range scored {
+		ranked[i] = s.post
+	}
+	return ranked
+}
+
+func rankingSettings() config.RankingConfig {
+	if config.AppConfig != nil {
+		return config.AppConfig.Ranking
+	}
+	return config.RankingConfig{
+		CandidateLimit: 500,
+		HalfLifeHours:  12,
+		RecencyWeight:  3,
+		LikeWeight:     1,
+		CommentWeight:  1.5,
+		AffinityWeight: 2,
+	}
+}
+
+// loadAffinity counts the viewer's likes and comments on posts by each of
+// the given authors.
+func loadAffinity(viewerID string, authorIDs []string) (map[string]int, error) {
+	affinity := make(map[string]int)
+	if len(authorIDs) == 0 {
+		return affinity, nil
+	}
+
+	query := `SELECT p.user_id, COUNT(*) FROM (
+			SELECT post_id FROM likes WHERE user_id = $1
+			UNION ALL
+			SELECT post_id FROM comments WHERE user_id = $1
+		) interactions JOIN posts p ON p.id = interactions.post_id
+		WHERE p.user_id = ANY($2)
+		GROUP BY p.user_id`
+	rows, err := database.DB.Query(query, viewerID, pq.Array(uniqueIDs(authorIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var authorID string
+		var count int
+		if err := rows.Scan(&authorID, &count); err != nil {
+			return nil, err
+		}
+		affinity[authorID] = count
+	}
+
+	return affinity, rows.Err()
+}
+
+// storedTime returns the instant a created_at value refers to. created_at
+// is stored as a timestamp without time zone holding local wall-clock time
+// and is read back labelled as UTC, so its wall clock is reinterpreted in
+// the local zone before it is compared with a real time.
+func storedTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"social-media-api/config"
+	"social-media-api/models"
+)
+
+func TestScorePost(t *testing.T) {
+	tests := []struct {
+		name     string
+		features RankingFeatures
+		weights  config.RankingConfig
+		want     float64
+	}{
+		{
+			name:     "brand new post gets the full recency weight",
+			features: RankingFeatures{AgeHours: 0},
+			weights:  config.RankingConfig{HalfLifeHours: 12, RecencyWeight: 3},
+			want:     3,
+		},
+		{
+			name:     "recency halves every half-life",
+			features: RankingFeatures{AgeHours: 12},
+			weights:  config.RankingConfig{HalfLifeHours: 12, RecencyWeight: 3},
+			want:     1.5,
+		},
+		{
+			name:     "recency after two half-lives",
+			features: RankingFeatures{AgeHours: 24},
+			weights:  config.RankingConfig{HalfLifeHours: 12, RecencyWeight: 4},
+			want:     1,
+		},
+		{
+			name:     "posts from the future count as brand new",
+			features: RankingFeatures{AgeHours: -5},
+			weights:  config.RankingConfig{HalfLifeHours: 12, RecencyWeight: 3},
+			want:     3,
+		},
+		{
+			name:     "zero half-life disables decay",
+			features: RankingFeatures{AgeHours: 1000},
+			weights:  config.RankingConfig{HalfLifeHours: 0, RecencyWeight: 3},
+			want:     3,
+		},
+		{
+			name:     "likes are log scaled",
+			features: RankingFeatures{LikeCount: 9},
+			weights:  config.RankingConfig{LikeWeight: 2},
+			want:     2 * math.Log(10),
+		},
+		{
+			name:     "comments are log scaled",
+			features: RankingFeatures{CommentCount: 4},
+			weights:  config.RankingConfig{CommentWeight: 1.5},
+			want:     1.5 * math.Log(5),
+		},
+		{
+			name:     "affinity is log scaled",
+			features: RankingFeatures{Affinity: 3},
+			weights:  config.RankingConfig{AffinityWeight: 2},
+			want:     2 * math.Log(4),
+		},
+		{
+			name:     "terms add up",
+			features: RankingFeatures{AgeHours: 12, LikeCount: 1, CommentCount: 1, Affinity: 1},
+			weights:  config.RankingConfig{HalfLifeHours: 12, RecencyWeight: 2, LikeWeight: 1, CommentWeight: 1, AffinityWeight: 1},
+			want:     1 + 3*math.Ln2,
+		},
+		{
+			name:     "zero weights ignore counts",
+			features: RankingFeatures{LikeCount: 100, CommentCount: 100, Affinity: 100},
+			weights:  config.RankingConfig{},
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScorePost(tt.features, tt.weights)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ScorePost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankPosts(t *testing.T) {
+	// created_at is read back as local wall-clock time labelled as UTC.
+	stored := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	now := storedTime(stored)
+	post := func(id, userID string, hoursAgo float64, likes, comments int) models.Post {
+		createdAt := stored.Add(-time.Duration(hoursAgo * float64(time.Hour)))
+		return models.Post{
+			ID:           id,
+			UserID:       userID,
+			CreatedAt:    createdAt.Format(time.RFC3339),
+			LikeCount:    likes,
+			CommentCount: comments,
+		}
+	}
+	weights := config.RankingConfig{HalfLifeHours: 12, RecencyWeight: 3, LikeWeight: 1, CommentWeight: 1.5, AffinityWeight: 2}
+
+	tests := []struct {
+		name     string
+		posts    []models.Post
+		affinity map[string]int
+		weights  config.RankingConfig
+		want     []string
+	}{
+		{
+			name:    "newer posts rank first when engagement is equal",
+			posts:   []models.Post{post("a", "u1", 24, 0, 0), post("b", "u1", 1, 0, 0), post("c", "u1", 6, 0, 0)},
+			weights: weights,
+			want:    []string{"b", "c", "a"},
+		},
+		{
+			name:    "engagement outweighs a small age difference",
+			posts:   []models.Post{post("a", "u1", 1, 0, 0), post("b", "u1", 3, 50, 10)},
+			weights: weights,
+			want:    []string{"b", "a"},
+		},
+		{
+			name:     "affinity lifts authors the viewer interacts with",
+			posts:    []models.Post{post("a", "u1", 1, 0, 0), post("b", "u2", 2, 0, 0)},
+			affinity: map[string]int{"u2": 20},
+			weights:  weights,
+			want:     []string{"b", "a"},
+		},
+		{
+			name:    "equal scores fall back to the newer post",
+			posts:   []models.Post{post("a", "u1", 5, 0, 0), post("b", "u1", 2, 0, 0)},
+			weights: config.RankingConfig{LikeWeight: 1},
+			want:    []string{"b", "a"},
+		},
+		{
+			name:    "equal scores and times fall back to the post ID",
+			posts:   []models.Post{post("a", "u1", 2, 0, 0), post("c", "u1", 2, 0, 0), post("b", "u1", 2, 0, 0)},
+			weights: weights,
+			want:    []string{"c", "b", "a"},
+		},
+		{
+			name:    "posts with an unreadable created_at are dropped",
+			posts:   []models.Post{post("a", "u1", 1, 0, 0), {ID: "b", UserID: "u1", CreatedAt: "yesterday"}},
+			weights: weights,
+			want:    []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := RankPosts(tt.posts, tt.affinity, tt.weights, now)
+			got := make([]string, len(ranked))
+			for i, post := range ranked {
+				got[i] = post.ID
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("RankPosts() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("RankPosts() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RepostService struct{}
+
+func NewRepostService() *RepostService {
+	return &RepostService{}
+}
+
+// resolveShareTarget returns the post userID would repost or quote when
+// sharing postID. Sharing a repost shares its original. Only public posts
+// can be shared so a repost never widens the original's audience.
+func resolveShareTarget(userID, postID string) (string, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = $1 AND ` + visiblePostFilter("posts", "$2")
+	post, err := scanPost(database.DB.QueryRow(query, postID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.New("post not found")
+		}
+		return "", err
+	}
+
+	if post.RepostOfID != nil {
+		return resolveShareTarget(userID, *post.RepostOfID)
+	}
+	if post.Visibility != "public" {
+		return "", errors.New("post cannot be shared")
+	}
+
+	return post.ID, nil
+}
+
+// Repost boosts postID into the timelines of userID's followers.
+func (s *RepostService) Repost(userID, postID string) (*models.Post, error) {
+	originalID, err := resolveShareTarget(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &models.Post{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Visibility: "public",
+		Status:     "published",
+		RepostOfID: &originalID,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	post.ConversationID = post.ID
+
+	query := `INSERT INTO posts (id, user_id, content, visibility, status, repost_of_id, conversation_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = database.DB.Exec(query, post.ID, post.UserID, post.Content, post.Visibility, post.Status,
+		post.RepostOfID, post.ConversationID, post.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, errors.New("post already reposted")
+		}
+		return nil, err
+	}
+
+	go fanOutPost(post.ID)
+	return post, nil
+}
+
+func (s *RepostService) Unrepost(userID, postID string) error {
+	var repostID string
+	query := `DELETE FROM posts WHERE user_id = $1 AND repost_of_id = $2 RETURNING id`
+	err := database.DB.QueryRow(query, userID, postID).Scan(&repostID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("repost not found")
+		}
+		return err
+	}
+
+	go removePostFromTimelines(userID, repostID)
+	return nil
+}
+
+// GetReposts lists the reposts and quotes of postID the viewer may see,
+// newest first.
+func (s *RepostService) GetReposts(viewerID, postID string, limit, offset int) ([]models.Post, error) {
+	if err := checkPostVisible(viewerID, postID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + postColumns + ` FROM posts
+		WHERE (repost_of_id = $1 OR quote_of_id = $1) AND ` + visiblePostFilter("posts", "$2") + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`
+	return queryPosts(query, postID, viewerID, limit, offset)
+}
+
+// attachSharedPosts fills in the original of every repost and quote the
+// viewer may see. Quotes whose original was deleted or is hidden from the
+// viewer are marked unavailable instead.
+func (s *PostService) attachSharedPosts(viewerID string, posts []models.Post) error {
+	var ids []string
+	for _, post := range posts {
+		if post.RepostOfID != nil {
+			ids = append(ids, *post.RepostOfID)
+		}
+		if post.QuoteOfID != nil {
+			ids = append(ids, *post.QuoteOfID)
+		}
+	}
+
+	originals := make(map[string]*models.Post)
+	if len(ids) > 0 {
+		query := `SELECT ` + postColumns + ` FROM posts WHERE id = ANY($1) AND ` + visiblePostFilter("posts", "$2")
+		loaded, err := queryPosts(query, pq.Array(uniqueIDs(ids)), viewerID)
+		if err != nil {
+			return err
+		}
+
+		if err := attachHashtags(loaded); err != nil {
+			return err
+		}
+		if err := attachPostMentions(loaded); err != nil {
+			return err
+		}
+		if err := attachPostMedia(loaded); err != nil {
+			return err
+		}
+		if err := s.AnnotatePostsForViewer(viewerID, loaded); err != nil {
+			return err
+		}
+
+		for i := range loaded {
+			originals[loaded[i].ID] = &loaded[i]
+		}
+	}
+
+	for i := range posts {
+		if posts[i].RepostOfID != nil {
+			posts[i].RepostOf = originals[*posts[i].RepostOfID]
+		}
+		if posts[i].IsQuote {
+			if posts[i].QuoteOfID != nil {
+				posts[i].QuotedPost = originals[*posts[i].QuoteOfID]
+			}
+			posts[i].QuoteUnavailable = posts[i].QuotedPost == nil
+		}
+	}
+
+	return nil
+}
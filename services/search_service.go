@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"social-media-api/database"
+	"social-media-api/models"
+	"social-media-api/utils"
+)
+
+// escapedContent is the post content HTML-escaped in SQL, so ts_headline
+// only ever adds its own <mark> tags to text that is safe to render.
+const escapedContent = `replace(replace(replace(replace(replace(content,
+	'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+type SearchService struct{}
+
+func NewSearchService() *SearchService {
+	return &SearchService{}
+}
+
+func (s *SearchService) SearchPosts(viewerID, q string, limit, offset int) ([]models.PostSearchResult, error) {
+	tsQuery, err := utils.BuildTSQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + postColumns + `,
+			ts_rank_cd(search_vector, query) AS rank,
+			ts_headline('simple', ` + escapedContent + `, query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet
+		FROM posts, to_tsquery('simple', $1) query
+		WHERE search_vector @@ query AND ` + visiblePostFilter("posts", "$4") + `
+			AND ` + notMutedFilter("user_id", "search_vector", "$4") + `
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := database.DB.Query(query, tsQuery, limit, offset, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.PostSearchResult{}
+	for rows.Next() {
+		var result models.PostSearchResult
+		err := rows.Scan(append(postFields(&result.Post), &result.Rank, &result.Snippet)...)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// SearchUsers matches q against username prefixes, and uses trigram
+// similarity on username and bio to tolerate typos. Exact username matches
+// and accounts the viewer follows are ranked higher.
+func (s *SearchService) SearchUsers(viewerID, q string, limit, offset int) ([]models.UserSearchResult, error) {
+	q = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(q), "@"))
+	if q == "" {
+		return nil, errors.New("invalid search query")
+	}
+
+	query := `SELECT ` + userColumns + `, score FROM (
+			SELECT u.*,
+				CASE WHEN LOWER(u.username) = LOWER($1) THEN 3 ELSE 0 END
+				+ CASE WHEN LOWER(u.username) LIKE LOWER($2) || '%' THEN 1.5 ELSE 0 END
+				+ similarity(u.username, $1)
+				+ 0.5 * word_similarity($1, COALESCE(u.bio, ''))
+				+ CASE WHEN f.follower_id IS NOT NULL THEN 1 ELSE 0 END AS score
+			FROM users u
+			LEFT JOIN follows f ON f.following_id = u.id AND f.follower_id = $3
+			WHERE (LOWER(u.username) LIKE LOWER($2) || '%'
+				OR u.username % $1
+				OR $1 <% COALESCE(u.bio, ''))
+				AND ` + notBlockedFilter("u.id", "$3") + `
+		) ranked
+		ORDER BY score DESC, username
+		LIMIT $4 OFFSET $5`
+	rows, err := database.DB.Query(query, q, utils.EscapeLike(q), viewerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.UserSearchResult{}
+	for rows.Next() {
+		var result models.UserSearchResult
+		err := rows.Scan(append(userFields(&result.User), &result.Score)...)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// AutocompleteUsernames suggests usernames starting with prefix, leaving
+// out users with a block in either direction with viewerID.
+func (s *SearchService) AutocompleteUsernames(viewerID, prefix string, limit int) ([]models.UserSummary, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "@")
+	if prefix == "" {
+		return []models.UserSummary{}, nil
+	}
+
+	query := `SELECT id, username FROM users
+		WHERE LOWER(username) LIKE LOWER($1) || '%'
+		AND ` + notBlockedFilter("id", "$3") + `
+		ORDER BY follower_count DESC, username
+		LIMIT $2`
+	rows, err := database.DB.Query(query, utils.EscapeLike(prefix), limit, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.UserSummary{}
+	for rows.Next() {
+		var user models.UserSummary
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+
+	"social-media-api/database"
+	"social-media-api/models"
+)
+
+const maxThreadDescendants = 500
+
+// resolveReplyParent returns the post userID replies to when replying to
+// postID. Replying to a repost replies to its original.
+func resolveReplyParent(userID, postID string) (*models.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = $1 AND ` + visiblePostFilter("posts", "$2")
+	parent, err := scanPost(database.DB.QueryRow(query, postID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("parent post not found")
+		}
+		return nil, err
+	}
+
+	if parent.RepostOfID != nil {
+		return resolveReplyParent(userID, *parent.RepostOfID)
+	}
+
+	return &parent, nil
+}
+
+// GetThread returns the chain of posts postID replies to, oldest first, and
+// the replies below it in reading order. Hidden or muted replies are left out
+// together with everything below them. When there are more replies than the
+// cap, the shallowest are kept and Truncated is set.
+func (s *PostService) GetThread(viewerID, postID string) (*models.Thread, error) {
+	post, err := s.GetPostByID(viewerID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestorQuery := `WITH RECURSIVE ancestors AS (
+			SELECT p.*, 1 AS depth FROM posts p WHERE p.id = $1
+			UNION ALL
+			SELECT p.*, a.depth + 1 FROM posts p JOIN ancestors a ON p.id = a.in_reply_to_id
+		)
+		SELECT ` + postColumns + ` FROM ancestors
+		WHERE ` + visiblePostFilter("ancestors", "$2") + `
+		ORDER BY depth DESC`
+	ancestors := []models.Post{}
+	if post.InReplyToID != nil {
+		ancestors, err = queryPosts(ancestorQuery, *post.InReplyToID, viewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	descendantQuery := `WITH RECURSIVE descendants AS (
+			SELECT p.*, 1 AS depth FROM posts p WHERE p.in_reply_to_id = $1
+				AND ` + visiblePostFilter("p", "$2") + `
+				AND ` + notMutedFilter("p.user_id", "p.search_vector", "$2") + `
+			UNION ALL
+			SELECT p.*, d.depth + 1 FROM posts p JOIN descendants d ON p.in_reply_to_id = d.id
+			WHERE ` + visiblePostFilter("p", "$2") + `
+				AND ` + notMutedFilter("p.user_id", "p.search_vector", "$2") + `
+		)
+		SELECT ` + postColumns + ` FROM descendants
+		ORDER BY depth, created_at, id
+		LIMIT $3`
+	descendants, err := queryPosts(descendantQuery, post.ID, viewerID, maxThreadDescendants+1)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := len(descendants) > maxThreadDescendants
+	if truncated {
+		descendants = descendants[:maxThreadDescendants]
+	}
+
+	authorID, err := conversationAuthor(*post)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Thread{
+		Ancestors:   ancestors,
+		Post:        *post,
+		Descendants: orderThread(*post, authorID, descendants),
+		Truncated:   truncated,
+	}, nil
+}
+
+// conversationAuthor returns the author of the post that started post's
+// conversation, or post's own author when that post is gone.
+func conversationAuthor(post models.Post) (string, error) {
+	var authorID string
+	query := `SELECT user_id FROM posts WHERE id = $1`
+	err := database.DB.QueryRow(query, post.ConversationID).Scan(&authorID)
+	if err == sql.ErrNoRows {
+		return post.UserID, nil
+	}
+	return authorID, err
+}
+
+// orderThread arranges replies depth first below root. Among the replies to
+// a post, those by the conversation's author come first, so the author's
+// self-thread reads contiguously before anyone else's replies; the rest stay
+// oldest first.
+func orderThread(root models.Post, authorID string, replies []models.Post) []models.Post {
+	children := make(map[string][]models.Post)
+	for _, reply := range replies {
+		parentID := *reply.InReplyToID
+		children[parentID] = append(children[parentID], reply)
+	}
+
+	ordered := make([]models.Post, 0, len(replies))
+	var walk func(parent models.Post)
+	walk = func(parent models.Post) {
+		siblings := children[parent.ID]
+		sort.SliceStable(siblings, func(i, j int) bool {
+			return siblings[i].UserID == authorID && siblings[j].UserID != authorID
+		})
+		for _, reply := range siblings {
+			ordered = append(ordered, reply)
+			walk(reply)
+		}
+	}
+	walk(root)
+
+	return ordered
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"social-media-api/config"
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/lib/pq"
+)
+
+func timelineSettings() config.TimelineConfig {
+	if config.AppConfig != nil {
+		return config.AppConfig.Timeline
+	}
+	return config.TimelineConfig{MaxLength: 800, FanoutLimit: 10000, MaxAgeSeconds: 300}
+}
+
+func timelineEntry(post models.Post) (TimelineEntry, error) {
+	createdAt, err := time.Parse(time.RFC3339Nano, post.CreatedAt)
+	if err != nil {
+		return TimelineEntry{}, err
+	}
+	return TimelineEntry{PostID: post.ID, AuthorID: post.UserID, CreatedAt: createdAt}, nil
+}
+
+// fanOutPost pushes a new post into the timelines of its author and, unless
+// the author has more followers than the fan-out limit, of every follower
+// allowed to see it. Posts by high-follower accounts are merged in at read
+// time instead.
+func fanOutPost(postID string) {
+	settings := timelineSettings()
+
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = $1`
+	post, err := scanPost(database.DB.QueryRow(query, postID))
+	if err != nil {
+		log.Printf("Timeline fan-out failed for post %s: %v", postID, err)
+		return
+	}
+
+	if post.Status != "published" {
+		return
+	}
+
+	entry, err := timelineEntry(post)
+	if err != nil {
+		log.Printf("Timeline fan-out failed for post %s: %v", postID, err)
+		return
+	}
+
+	timelineStore.Push(post.UserID, entry, settings.MaxLength)
+
+	followers, err := fanOutFollowers(post.UserID, settings.FanoutLimit)
+	if err == nil && (post.Visibility != "public" || post.RepostOfID != nil) {
+		followers, err = postAudience(post.ID, followers)
+	}
+	if err != nil {
+		log.Printf("Timeline fan-out failed for post %s: %v", postID, err)
+		return
+	}
+
+	for _, followerID := range followers {
+		timelineStore.Push(followerID, entry, settings.MaxLength)
+	}
+}
+
+func removePostFromTimelines(authorID, postID string) {
+	timelineStore.Remove(authorID, postID)
+
+	followers, err := fanOutFollowers(authorID, -1)
+	if err != nil {
+		log.Printf("Timeline cleanup failed for post %s: %v", postID, err)
+		return
+	}
+
+	for _, followerID := range followers {
+		timelineStore.Remove(followerID, postID)
+	}
+}
+
+// followerCountChanged invalidates the timelines of authorID's followers when
+// a change of delta followers moved the author across the fan-out limit.
+// Timelines built under the old mode either lack the author's recent posts
+// or hold entries that are now merged at read time, so they are rebuilt.
+func followerCountChanged(authorID string, delta int) {
+	if delta == 0 {
+		return
+	}
+	limit := timelineSettings().FanoutLimit
+
+	var followerCount int
+	countQuery := `SELECT follower_count FROM users WHERE id = $1`
+	if err := database.DB.QueryRow(countQuery, authorID).Scan(&followerCount); err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Timeline fan-out check failed for user %s: %v", authorID, err)
+		}
+		return
+	}
+	if (followerCount-delta > limit) == (followerCount > limit) {
+		return
+	}
+
+	followers, err := fanOutFollowers(authorID, -1)
+	if err != nil {
+		log.Printf("Timeline fan-out check failed for user %s: %v", authorID, err)
+		return
+	}
+	for _, followerID := range followers {
+		timelineStore.Invalidate(followerID)
+	}
+}
+
+// fanOutFollowers returns the followers of authorID, or nothing when the
+// author has more than limit followers. A negative limit returns everyone.
+func fanOutFollowers(authorID string, limit int) ([]string, error) {
+	if limit >= 0 {
+		var followerCount int
+		countQuery := `SELECT follower_count FROM users WHERE id = $1`
+		if err := database.DB.QueryRow(countQuery, authorID).Scan(&followerCount); err != nil {
+			return nil, err
+		}
+		if followerCount > limit {
+			return nil, nil
+		}
+	}
+
+	query := `SELECT follower_id FROM follows WHERE following_id = $1`
+	rows, err := database.DB.Query(query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followers []string
+	for rows.Next() {
+		var followerID string
+		if err := rows.Scan(&followerID); err != nil {
+			return nil, err
+		}
+		followers = append(followers, followerID)
+	}
+
+	return followers, rows.Err()
+}
+
+// postAudience narrows userIDs down to the users allowed to see the post.
+func postAudience(postID string, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT u.id FROM users u JOIN posts ON posts.id = $2
+		WHERE u.id = ANY($1) AND ` + visiblePostFilter("posts", "u.id")
+	audience, err := queryIDSet(query, pq.Array(userIDs), postID)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed []string
+	for _, userID := range userIDs {
+		if audience[userID] {
+			allowed = append(allowed, userID)
+		}
+	}
+
+	return allowed, nil
+}
+
+func rebuildTimeline(userID string) error {
+	settings := timelineSettings()
+
+	query := `SELECT ` + postColumns + ` FROM posts
+		WHERE (user_id = $1 OR user_id IN (
+			SELECT f.following_id FROM follows f JOIN users u ON u.id = f.following_id
+			WHERE f.follower_id = $1 AND u.follower_count <= $2))
+		AND ` + visiblePostFilter("posts", "$1") + `
+		ORDER BY created_at DESC, id DESC LIMIT $3`
+	rows, err := database.DB.Query(query, userID, settings.FanoutLimit, settings.MaxLength)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var entries []TimelineEntry
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return err
+		}
+		entry, err := timelineEntry(post)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	timelineStore.Set(userID, entries, len(entries) < settings.MaxLength)
+	return nil
+}
+
+func loadPostsByIDs(viewerID string, ids []string) (map[string]models.Post, error) {
+	posts := make(map[string]models.Post)
+	if len(ids) == 0 {
+		return posts, nil
+	}
+
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = ANY($1) AND ` + visiblePostFilter("posts", "$2")
+	rows, err := database.DB.Query(query, pq.Array(ids), viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts[post.ID] = post
+	}
+
+	return posts, rows.Err()
+}
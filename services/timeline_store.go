@@ -0,0 +1,169 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type TimelineEntry struct {
+	PostID    string
+	AuthorID  string
+	CreatedAt time.Time
+}
+
+// TimelineStore holds precomputed home timelines, newest entry first.
+// Implementations must be safe for concurrent use.
+type TimelineStore interface {
+	// Has reports whether a timeline was built for the user within maxAge.
+	// A zero maxAge accepts a timeline of any age.
+	Has(userID string, maxAge time.Duration) bool
+	// Set replaces the user's timeline. complete is false when older
+	// entries exist in the database but were left out.
+	Set(userID string, entries []TimelineEntry, complete bool)
+	// Push adds an entry to an existing timeline, replacing any entry for
+	// the same post, and trims it to maxLength. Users without a timeline are
+	// skipped; theirs is built on next read.
+	Push(userID string, entry TimelineEntry, maxLength int)
+	// Range returns up to limit entries older than before (or the newest
+	// entries when before is nil). ok is false when the store cannot
+	// answer and the caller must read from the database instead.
+	Range(userID string, before *TimelineEntry, limit int) (entries []TimelineEntry, ok bool)
+	Remove(userID, postID string)
+	RemoveAuthor(userID, authorID string)
+	Invalidate(userID string)
+}
+
+var timelineStore TimelineStore = NewMemoryTimelineStore()
+
+func SetTimelineStore(store TimelineStore) {
+	timelineStore = store
+}
+
+type memoryTimeline struct {
+	entries  []TimelineEntry
+	complete bool
+	builtAt  time.Time
+}
+
+type MemoryTimelineStore struct {
+	mu        sync.RWMutex
+	timelines map[string]*memoryTimeline
+}
+
+func NewMemoryTimelineStore() *MemoryTimelineStore {
+	return &MemoryTimelineStore{timelines: make(map[string]*memoryTimeline)}
+}
+
+func (m *MemoryTimelineStore) Has(userID string, maxAge time.Duration) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	timeline, ok := m.timelines[userID]
+	if !ok {
+		return false
+	}
+	return maxAge <= 0 || time.Since(timeline.builtAt) < maxAge
+}
+
+func (m *MemoryTimelineStore) Set(userID string, entries []TimelineEntry, complete bool) {
+	copied := make([]TimelineEntry, len(entries))
+	copy(copied, entries)
+	sort.Slice(copied, func(i, j int) bool { return newerEntry(copied[i], copied[j]) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timelines[userID] = &memoryTimeline{entries: copied, complete: complete, builtAt: time.Now()}
+}
+
+func (m *MemoryTimelineStore) Push(userID string, entry TimelineEntry, maxLength int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timeline, ok := m.timelines[userID]
+	if !ok {
+		return
+	}
+
+	for i, existing := range timeline.entries {
+		if existing.PostID == entry.PostID {
+			timeline.entries = append(timeline.entries[:i], timeline.entries[i+1:]...)
+			break
+		}
+	}
+
+	i := sort.Search(len(timeline.entries), func(i int) bool { return newerEntry(entry, timeline.entries[i]) })
+	timeline.entries = append(timeline.entries, TimelineEntry{})
+	copy(timeline.entries[i+1:], timeline.entries[i:])
+	timeline.entries[i] = entry
+
+	if maxLength > 0 && len(timeline.entries) > maxLength {
+		timeline.entries = timeline.entries[:maxLength]
+		timeline.complete = false
+	}
+}
+
+func (m *MemoryTimelineStore) Range(userID string, before *TimelineEntry, limit int) ([]TimelineEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	timeline, ok := m.timelines[userID]
+	if !ok {
+		return nil, false
+	}
+
+	start := 0
+	if before != nil {
+		start = sort.Search(len(timeline.entries), func(i int) bool { return newerEntry(*before, timeline.entries[i]) })
+	}
+
+	end := start + limit
+	if end > len(timeline.entries) {
+		if !timeline.complete {
+			return nil, false
+		}
+		end = len(timeline.entries)
+	}
+
+	entries := make([]TimelineEntry, end-start)
+	copy(entries, timeline.entries[start:end])
+	return entries, true
+}
+
+func (m *MemoryTimelineStore) Remove(userID, postID string) {
+	m.removeWhere(userID, func(entry TimelineEntry) bool { return entry.PostID == postID })
+}
+
+func (m *MemoryTimelineStore) RemoveAuthor(userID, authorID string) {
+	m.removeWhere(userID, func(entry TimelineEntry) bool { return entry.AuthorID == authorID })
+}
+
+func (m *MemoryTimelineStore) Invalidate(userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.timelines, userID)
+}
+
+func (m *MemoryTimelineStore) removeWhere(userID string, match func(TimelineEntry) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timeline, ok := m.timelines[userID]
+	if !ok {
+		return
+	}
+
+	kept := timeline.entries[:0]
+	for _, entry := range timeline.entries {
+		if !match(entry) {
+			kept = append(kept, entry)
+		}
+	}
+	timeline.entries = kept
+}
+
+func newerEntry(a, b TimelineEntry) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.PostID > b.PostID
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"social-media-api/config"
+	"social-media-api/database"
+	"social-media-api/models"
+
+	"github.com/lib/pq"
+)
+
+type TrendingService struct{}
+
+func NewTrendingService() *TrendingService {
+	return &TrendingService{}
+}
+
+// trendingPostScore is a cached trending post. Only the ID and score are
+// kept; the post itself is loaded for each viewer so visibility, deletions
+// and viewer-relative fields are always current.
+type trendingPostScore struct {
+	PostID string
+	Score  float64
+}
+
+type trendingSnapshot struct {
+	mu       sync.RWMutex
+	posts    []trendingPostScore
+	hashtags []models.TrendingHashtag
+}
+
+var trendingCache = &trendingSnapshot{}
+
+func trendingSettings() config.TrendingConfig {
+	if config.AppConfig != nil {
+		return config.AppConfig.Trending
+	}
+	return config.TrendingConfig{WindowHours: 24, RefreshMinutes: 5, Limit: 50}
+}
+
+// StartTrendingWorker refreshes the trending cache immediately and then on
+// every refresh interval for the lifetime of the process.
+func StartTrendingWorker() {
+	settings := trendingSettings()
+	interval := time.Duration(settings.RefreshMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := RefreshTrending(); err != nil {
+				log.Printf("Failed to refresh trending: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func RefreshTrending() error {
+	settings := trendingSettings()
+	since := time.Now().Add(-time.Duration(settings.WindowHours) * time.Hour).Format(time.RFC3339)
+
+	posts, err := computeTrendingPosts(since, settings.Limit)
+	if err != nil {
+		return err
+	}
+
+	hashtags, err := computeTrendingHashtags(since, settings.Limit)
+	if err != nil {
+		return err
+	}
+
+	trendingCache.mu.Lock()
+	trendingCache.posts = posts
+	trendingCache.hashtags = hashtags
+	trendingCache.mu.Unlock()
+
+	return nil
+}
+
+// computeTrendingPosts scores posts by the likes and comments they received
+// inside the window, plus a point for being created inside it. Posts from
+// private accounts and posts that are not public or not yet published are
+// left out since the cache is shared by all viewers, and so are reposts.
+func computeTrendingPosts(since string, limit int) ([]trendingPostScore, error) {
+	query := `SELECT id, score FROM (
+			SELECT p.*,
+				(SELECT COUNT(*) FROM likes l WHERE l.post_id = p.id AND l.created_at >= $1)
+				+ 2 * (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id AND c.created_at >= $1)
+				+ CASE WHEN p.created_at >= $1 THEN 1 ELSE 0 END AS score
+			FROM posts p
+			WHERE (p.created_at >= $1
+				OR p.id IN (SELECT post_id FROM likes WHERE created_at >= $1)
+				OR p.id IN (SELECT post_id FROM comments WHERE created_at >= $1))
+				AND p.status = 'published' AND p.visibility = 'public' AND p.repost_of_id IS NULL AND p.user_id NOT IN (SELECT id FROM users WHERE is_private)
+		) scored
+		ORDER BY score DESC, created_at DESC, id DESC
+		LIMIT $2`
+	rows, err := database.DB.Query(query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trending := []trendingPostScore{}
+	for rows.Next() {
+		var item trendingPostScore
+		if err := rows.Scan(&item.PostID, &item.Score); err != nil {
+			return nil, err
+		}
+		trending = append(trending, item)
+	}
+
+	return trending, rows.Err()
+}
+
+// computeTrendingHashtags ranks hashtags used in posts created inside the
+// window. Each use counts once, plus the engagement its post received.
+func computeTrendingHashtags(since string, limit int) ([]models.TrendingHashtag, error) {
+	query := `SELECT h.tag, COUNT(*) AS post_count, SUM(
+			1 + (SELECT COUNT(*) FROM likes l WHERE l.post_id = p.id AND l.created_at >= $1)
+			+ 2 * (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id AND c.created_at >= $1)
+		) AS score
+		FROM post_hashtags ph
+		JOIN hashtags h ON h.id = ph.hashtag_id
+		JOIN posts p ON p.id = ph.post_id
+		WHERE p.created_at >= $1 AND p.status = 'published' AND p.visibility = 'public' AND p.user_id NOT IN (SELECT id FROM users WHERE is_private)
+		GROUP BY h.tag
+		ORDER BY score DESC, h.tag
+		LIMIT $2`
+	rows, err := database.DB.Query(query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trending := []models.TrendingHashtag{}
+	for rows.Next() {
+		var item models.TrendingHashtag
+		if err := rows.Scan(&item.Tag, &item.PostCount, &item.Score); err != nil {
+			return nil, err
+		}
+		trending = append(trending, item)
+	}
+
+	return trending, rows.Err()
+}
+
+// GetTrendingPosts returns up to limit cached trending posts the viewer may
+// see, in score order. Posts deleted or hidden since the last refresh are
+// skipped.
+func (s *TrendingService) GetTrendingPosts(viewerID string, limit int) ([]models.TrendingPost, error) {
+	trendingCache.mu.RLock()
+	scores := make([]trendingPostScore, len(trendingCache.posts))
+	copy(scores, trendingCache.posts)
+	trendingCache.mu.RUnlock()
+
+	ids := make([]string, len(scores))
+	for i, item := range scores {
+		ids[i] = item.PostID
+	}
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = ANY($1) AND ` + visiblePostFilter("posts", "$2")
+	loaded, err := queryPosts(query, pq.Array(ids), viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[string]models.Post, len(loaded))
+	for _, post := range loaded {
+		visible[post.ID] = post
+	}
+
+	trending := []models.TrendingPost{}
+	for _, item := range scores {
+		if len(trending) == limit {
+			break
+		}
+		if post, found := visible[item.PostID]; found {
+			trending = append(trending, models.TrendingPost{Post: post, Score: item.Score})
+		}
+	}
+
+	return trending, nil
+}
+
+func (s *TrendingService) GetTrendingHashtags(limit int) []models.TrendingHashtag {
+	trendingCache.mu.RLock()
+	defer trendingCache.mu.RUnlock()
+
+	if limit > len(trendingCache.hashtags) {
+		limit = len(trendingCache.hashtags)
+	}
+	hashtags := make([]models.TrendingHashtag, limit)
+	copy(hashtags, trendingCache.hashtags[:limit])
+	return hashtags
+}
@@ -3,17 +3,105 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"sort"
 	"strings"
+	"time"
 
+	"social-media-api/config"
 	"social-media-api/database"
 	"social-media-api/models"
 	"social-media-api/utils"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type UserService struct{}
 
+const userColumns = `id, username, email, bio, display_name, avatar_url, banner_url, location, website,
+	COALESCE(TO_CHAR(birthday, 'YYYY-MM-DD'), '') AS birthday, birthday_visibility, pronouns, profile_fields,
+	is_private, joined_at, post_count, follower_count, following_count`
+
+const (
+	maxDisplayNameLength  = 50
+	maxLocationLength     = 100
+	maxPronounsLength     = 40
+	maxURLLength          = 500
+	maxProfileFields      = 4
+	maxProfileFieldName   = 32
+	maxProfileFieldValue  = 100
+	defaultBirthdayAccess = "private"
+)
+
+var birthdayVisibilities = map[string]bool{"public": true, "followers": true, "private": true}
+
+func userFields(user *models.User) []interface{} {
+	return []interface{}{
+		&user.ID, &user.Username, &user.Email, &user.Bio, &user.DisplayName, &user.AvatarURL, &user.BannerURL,
+		&user.Location, &user.Website, &user.Birthday, &user.BirthdayVisibility, &user.Pronouns, &user.ProfileFields,
+		&user.IsPrivate, &user.JoinedAt, &user.PostCount, &user.FollowerCount, &user.FollowingCount,
+	}
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(userFields(&user)...)
+	return user, err
+}
+
+func validateProfile(user *models.User) error {
+	if utils.CharLength(user.DisplayName) > maxDisplayNameLength {
+		return errors.New("display name is too long")
+	}
+	if utils.CharLength(user.Location) > maxLocationLength {
+		return errors.New("location is too long")
+	}
+	if utils.CharLength(user.Pronouns) > maxPronounsLength {
+		return errors.New("pronouns is too long")
+	}
+	if user.Website != "" && (len(user.Website) > maxURLLength || !utils.IsValidURL(user.Website)) {
+		return errors.New("invalid website url")
+	}
+	if user.AvatarURL != "" && (len(user.AvatarURL) > maxURLLength || !utils.IsValidURL(user.AvatarURL)) {
+		return errors.New("invalid avatar url")
+	}
+	if user.BannerURL != "" && (len(user.BannerURL) > maxURLLength || !utils.IsValidURL(user.BannerURL)) {
+		return errors.New("invalid banner url")
+	}
+	if user.Birthday != "" {
+		if !utils.IsValidDate(user.Birthday) || user.Birthday > time.Now().Format("2006-01-02") {
+			return errors.New("invalid birthday")
+		}
+	}
+	if user.BirthdayVisibility == "" {
+		user.BirthdayVisibility = defaultBirthdayAccess
+	}
+	if !birthdayVisibilities[user.BirthdayVisibility] {
+		return errors.New("invalid birthday visibility")
+	}
+	if len(user.ProfileFields) > maxProfileFields {
+		return errors.New("too many profile fields")
+	}
+	for _, field := range user.ProfileFields {
+		name := strings.TrimSpace(field.Name)
+		if name == "" || utils.CharLength(name) > maxProfileFieldName || utils.CharLength(field.Value) > maxProfileFieldValue {
+			return errors.New("invalid profile field")
+		}
+	}
+	if user.ProfileFields == nil {
+		user.ProfileFields = models.ProfileFields{}
+	}
+
+	return nil
+}
+
+func nullableDate(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
 func NewUserService() *UserService {
 	return &UserService{}
 }
@@ -28,11 +116,33 @@ func (s *UserService) CreateUser(user *models.User) error {
 	if !utils.IsValidEmail(user.Email) {
 		return errors.New("invalid email format")
 	}
+	if err := validateProfile(user); err != nil {
+		return err
+	}
 
 	user.ID = uuid.New().String()
+	joinedAt := time.Now().Format(time.RFC3339)
+	user.JoinedAt = &joinedAt
 
-	query := `INSERT INTO users (id, username, email, bio) VALUES ($1, $2, $3, $4)`
-	_, err := database.DB.Exec(query, user.ID, user.Username, user.Email, user.Bio)
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockUsernames(tx, user.Username); err != nil {
+		return err
+	}
+	if err := checkUsernameAvailable(tx, user.Username, user.ID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO users (id, username, email, bio, display_name, avatar_url, banner_url, location, website,
+		birthday, birthday_visibility, pronouns, profile_fields, is_private, joined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	_, err = tx.Exec(query, user.ID, user.Username, user.Email, user.Bio, user.DisplayName, user.AvatarURL,
+		user.BannerURL, user.Location, user.Website, nullableDate(user.Birthday), user.BirthdayVisibility, user.Pronouns,
+		user.ProfileFields, user.IsPrivate, user.JoinedAt)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
 			return errors.New("username or email already exists")
@@ -40,11 +150,16 @@ func (s *UserService) CreateUser(user *models.User) error {
 		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	user.SetViewer(user.ID, false)
 	return nil
 }
 
 func (s *UserService) GetAllUsers() ([]models.User, error) {
-	query := `SELECT id, username, email, bio FROM users ORDER BY username`
+	query := `SELECT ` + userColumns + ` FROM users ORDER BY username`
 	rows, err := database.DB.Query(query)
 	if err != nil {
 		return nil, err
@@ -53,8 +168,7 @@ func (s *UserService) GetAllUsers() ([]models.User, error) {
 
 	var users []models.User
 	for rows.Next() {
-		var user models.User
-		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Bio)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -65,9 +179,8 @@ func (s *UserService) GetAllUsers() ([]models.User, error) {
 }
 
 func (s *UserService) GetUserByID(id string) (*models.User, error) {
-	var user models.User
-	query := `SELECT id, username, email, bio FROM users WHERE id = $1`
-	err := database.DB.QueryRow(query, id).Scan(&user.ID, &user.Username, &user.Email, &user.Bio)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	user, err := scanUser(database.DB.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("user not found")
@@ -78,38 +191,160 @@ func (s *UserService) GetUserByID(id string) (*models.User, error) {
 	return &user, nil
 }
 
-func (s *UserService) UpdateUser(id string, user *models.User) error {
+func (s *UserService) GetUserByUsername(username string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE LOWER(username) = LOWER($1)`
+	user, err := scanUser(database.DB.QueryRow(query, strings.TrimPrefix(username, "@")))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetRenamedUsername returns the current username of the account that most
+// recently released the given username, for redirecting stale links.
+func (s *UserService) GetRenamedUsername(username string) (string, error) {
+	query := `SELECT u.username FROM username_history h
+		JOIN users u ON u.id = h.user_id
+		WHERE LOWER(h.username) = LOWER($1)
+		ORDER BY h.released_at DESC
+		LIMIT 1`
+	var current string
+	err := database.DB.QueryRow(query, strings.TrimPrefix(username, "@")).Scan(&current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.New("user not found")
+		}
+		return "", err
+	}
+
+	return current, nil
+}
+
+// applyUserUpdate copies the fields set in update onto user.
+func applyUserUpdate(user *models.User, update *models.UserUpdate) {
+	fields := []struct {
+		value  *string
+		target *string
+	}{
+		{update.Username, &user.Username},
+		{update.Email, &user.Email},
+		{update.Bio, &user.Bio},
+		{update.DisplayName, &user.DisplayName},
+		{update.AvatarURL, &user.AvatarURL},
+		{update.BannerURL, &user.BannerURL},
+		{update.Location, &user.Location},
+		{update.Website, &user.Website},
+		{update.Birthday, &user.Birthday},
+		{update.BirthdayVisibility, &user.BirthdayVisibility},
+		{update.Pronouns, &user.Pronouns},
+	}
+	for _, field := range fields {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+
+	if update.ProfileFields != nil {
+		user.ProfileFields = *update.ProfileFields
+	}
+	if update.IsPrivate != nil {
+		user.IsPrivate = *update.IsPrivate
+	}
+}
+
+func (s *UserService) UpdateUser(id string, update *models.UserUpdate) (*models.User, error) {
+	existingUser, err := s.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	user := *existingUser
+	applyUserUpdate(&user, update)
 	if user.Username == "" {
-		return errors.New("username is required")
+		return nil, errors.New("username is required")
 	}
 	if user.Email == "" {
-		return errors.New("email is required")
+		return nil, errors.New("email is required")
 	}
 	if !utils.IsValidEmail(user.Email) {
-		return errors.New("invalid email format")
+		return nil, errors.New("invalid email format")
+	}
+	if err := validateProfile(&user); err != nil {
+		return nil, err
 	}
 
-	var existingUser models.User
-	checkQuery := `SELECT id FROM users WHERE id = $1`
-	err := database.DB.QueryRow(checkQuery, id).Scan(&existingUser.ID)
+	tx, err := database.DB.Begin()
 	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Re-read the username under a row lock so concurrent renames of this
+	// account release the right name.
+	lockQuery := `SELECT username FROM users WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(lockQuery, id).Scan(&existingUser.Username); err != nil {
 		if err == sql.ErrNoRows {
-			return errors.New("user not found")
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	renamed := !strings.EqualFold(existingUser.Username, user.Username)
+	if renamed {
+		if err := lockUsernames(tx, existingUser.Username, user.Username); err != nil {
+			return nil, err
+		}
+		if err := checkUsernameAvailable(tx, user.Username, id); err != nil {
+			return nil, err
 		}
-		return err
 	}
 
-	query := `UPDATE users SET username = $1, email = $2, bio = $3 WHERE id = $4`
-	_, err = database.DB.Exec(query, user.Username, user.Email, user.Bio, id)
+	query := `UPDATE users SET username = $1, email = $2, bio = $3, display_name = $4, avatar_url = $5,
+		banner_url = $6, location = $7, website = $8, birthday = $9, birthday_visibility = $10, pronouns = $11,
+		profile_fields = $12, is_private = $13
+		WHERE id = $14`
+	_, err = tx.Exec(query, user.Username, user.Email, user.Bio, user.DisplayName, user.AvatarURL, user.BannerURL,
+		user.Location, user.Website, nullableDate(user.Birthday), user.BirthdayVisibility, user.Pronouns,
+		user.ProfileFields, user.IsPrivate, id)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
-			return errors.New("username or email already exists")
+			return nil, errors.New("username or email already exists")
 		}
-		return err
+		return nil, err
 	}
 
-	user.ID = id
-	return nil
+	if renamed {
+		historyQuery := `INSERT INTO username_history (id, user_id, username, released_at)
+			VALUES ($1, $2, $3, CURRENT_TIMESTAMP)`
+		_, err = tx.Exec(historyQuery, uuid.New().String(), id, existingUser.Username)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Going public accepts everyone who was waiting for approval.
+	var approved []string
+	if existingUser.IsPrivate && !user.IsPrivate {
+		approved, err = approveAllFollowRequests(tx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for _, followerID := range approved {
+		timelineStore.Invalidate(followerID)
+	}
+	followerCountChanged(id, len(approved))
+
+	return s.GetUserByID(id)
 }
 
 func (s *UserService) DeleteUser(id string) error {
@@ -123,11 +358,127 @@ func (s *UserService) DeleteUser(id string) error {
 		return err
 	}
 
+	followingQuery := `SELECT following_id FROM follows WHERE follower_id = $1`
+	following, err := queryIDList(followingQuery, id)
+	if err != nil {
+		return err
+	}
+
 	query := `DELETE FROM users WHERE id = $1`
 	_, err = database.DB.Exec(query, id)
 	if err != nil {
 		return err
 	}
 
+	for _, followingID := range following {
+		followerCountChanged(followingID, -1)
+	}
+
+	return nil
+}
+
+// PrepareUsers fills in viewer-relative fields and lets the user and admins
+// see private fields and followers see birthdays shared with followers.
+// viewerID must be a verified identity, or empty for an anonymous viewer.
+func (s *UserService) PrepareUsers(viewerID string, users []models.User) error {
+	if err := s.AnnotateUsersForViewer(viewerID, users); err != nil {
+		return err
+	}
+	if viewerID == "" {
+		return nil
+	}
+
+	admin := IsAdmin(viewerID)
+	for i := range users {
+		users[i].SetViewer(viewerID, admin)
+	}
+
+	return nil
+}
+
+func IsAdmin(userID string) bool {
+	if userID == "" || config.AppConfig == nil {
+		return false
+	}
+	for _, adminID := range config.AppConfig.AdminUserIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *UserService) AnnotateUsersForViewer(viewerID string, users []models.User) error {
+	if viewerID == "" || len(users) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	followingQuery := `SELECT following_id FROM follows WHERE follower_id = $1 AND following_id = ANY($2)`
+	following, err := queryIDSet(followingQuery, viewerID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	followerQuery := `SELECT follower_id FROM follows WHERE following_id = $1 AND follower_id = ANY($2)`
+	followers, err := queryIDSet(followerQuery, viewerID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		followedByMe := following[users[i].ID]
+		followsMe := followers[users[i].ID]
+		users[i].FollowedByMe = &followedByMe
+		users[i].FollowsMe = &followsMe
+	}
+
+	return nil
+}
+
+// lockUsernames takes transaction-scoped locks on the given usernames, in a
+// fixed order, so a rename that releases a username and a claim on that same
+// username cannot both pass the cooldown check.
+func lockUsernames(tx *sql.Tx, usernames ...string) error {
+	keys := make([]string, len(usernames))
+	for i, username := range usernames {
+		keys[i] = strings.ToLower(username)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('username:' || $1))`, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUsernameAvailable rejects usernames released by another account
+// within the cooldown period, so old links cannot be taken over right away.
+// It runs inside the transaction that claims the username, after
+// lockUsernames.
+func checkUsernameAvailable(tx *sql.Tx, username, userID string) error {
+	cooldownDays := 30
+	if config.AppConfig != nil {
+		cooldownDays = config.AppConfig.UsernameCooldownDays
+	}
+
+	var reserved bool
+	query := `SELECT EXISTS(SELECT 1 FROM username_history
+		WHERE LOWER(username) = LOWER($1) AND user_id != $2
+		AND released_at > CURRENT_TIMESTAMP - make_interval(days => $3))`
+	err := tx.QueryRow(query, username, userID, cooldownDays).Scan(&reserved)
+	if err != nil {
+		return err
+	}
+	if reserved {
+		return errors.New("username is reserved")
+	}
+
 	return nil
 }
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+const MaxHashtagLength = 100
+
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}\p{M}_&/#])#([\p{L}\p{N}\p{M}_]+)`)
+
+func ExtractHashtags(content string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+
+	for _, match := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		tag := NormalizeHashtag(match[1])
+		if !IsValidHashtag(tag) || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// IsValidHashtag rejects empty, overlong and purely numeric tags such as
+// "#1", which are usually issue numbers or rankings rather than topics.
+func IsValidHashtag(tag string) bool {
+	if tag == "" || len([]rune(tag)) > MaxHashtagLength {
+		return false
+	}
+	for _, r := range tag {
+		if !('0' <= r && r <= '9') && r != '_' {
+			return true
+		}
+	}
+	return false
+}
+
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "tags are lowercased and deduplicated", content: "Belajar #Go dan #golang, lalu #GO lagi", want: []string{"go", "golang"}},
+		{name: "multi-byte tags", content: "Liburan ke #日本 dan #東京!", want: []string{"日本", "東京"}},
+		{name: "underscores are part of the tag", content: "#makanan_enak", want: []string{"makanan_enak"}},
+		{name: "combining marks are part of the tag", content: "#cafe\u0301 time", want: []string{"cafe\u0301"}},
+		{name: "tag after multi-byte punctuation", content: "「#kopi」", want: []string{"kopi"}},
+		{name: "no tag inside a word", content: "C#sharp 日本#tag", want: nil},
+		{name: "no tag in URL fragments or entities", content: "http://example.com/#anchor &#39; ##double", want: nil},
+		{name: "numeric tags are ignored", content: "#1 #2024 #top10", want: []string{"top10"}},
+		{name: "overlong tags are ignored", content: "#" + strings.Repeat("a", MaxHashtagLength+1), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractHashtags(tt.content)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractHashtags(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ExtractHashtags(%q) = %q, want %q", tt.content, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package utils
+
+import "strings"
+
+func ParseIncludes(param string) map[string]bool {
+	includes := make(map[string]bool)
+	for _, value := range strings.Split(param, ",") {
+		if value = strings.TrimSpace(strings.ToLower(value)); value != "" {
+			includes[value] = true
+		}
+	}
+	return includes
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+type MentionCandidate struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@./])(@([\p{L}\p{N}_]+))`)
+
+// ExtractMentions finds @username tokens in content. Offset and Length are
+// measured in Unicode code points and cover the leading "@".
+func ExtractMentions(content string) []MentionCandidate {
+	var mentions []MentionCandidate
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2], match[3]
+		mentions = append(mentions, MentionCandidate{
+			Username: content[match[4]:match[5]],
+			Offset:   utf8.RuneCountInString(content[:start]),
+			Length:   utf8.RuneCountInString(content[start:end]),
+		})
+	}
+	return mentions
+}
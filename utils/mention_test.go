@@ -0,0 +1,40 @@
+package utils
+
+import "testing"
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []MentionCandidate
+	}{
+		{name: "mention at the start", content: "@alice hi", want: []MentionCandidate{{Username: "alice", Offset: 0, Length: 6}}},
+		{name: "mention after text", content: "hi @alice", want: []MentionCandidate{{Username: "alice", Offset: 3, Length: 6}}},
+		{
+			name:    "offsets count code points after multi-byte text",
+			content: "こんにちは @bob と @carol",
+			want:    []MentionCandidate{{Username: "bob", Offset: 6, Length: 4}, {Username: "carol", Offset: 13, Length: 6}},
+		},
+		{name: "emoji count as one code point", content: "😀 @bob", want: []MentionCandidate{{Username: "bob", Offset: 2, Length: 4}}},
+		{name: "multi-byte username", content: "halo @日本語", want: []MentionCandidate{{Username: "日本語", Offset: 5, Length: 4}}},
+		{name: "mention inside punctuation", content: "(@dave)", want: []MentionCandidate{{Username: "dave", Offset: 1, Length: 5}}},
+		{name: "email addresses are not mentions", content: "mail a@b.com", want: nil},
+		{name: "no mention right after another", content: "@alice@bob", want: []MentionCandidate{{Username: "alice", Offset: 0, Length: 6}}},
+		{name: "double at sign", content: "@@dave", want: nil},
+		{name: "bare at sign", content: "@ nobody", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractMentions(tt.content)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractMentions(%q) = %+v, want %+v", tt.content, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ExtractMentions(%q) = %+v, want %+v", tt.content, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+func ParseLimit(limitParam string) (int, error) {
+	limit := DefaultPageLimit
+	if limitParam != "" {
+		value, err := strconv.Atoi(limitParam)
+		if err != nil || value < 1 {
+			return 0, errors.New("invalid limit")
+		}
+		limit = value
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	return limit, nil
+}
+
+func ParsePagination(limitParam, offsetParam string) (int, int, error) {
+	limit, err := ParseLimit(limitParam)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset := 0
+	if offsetParam != "" {
+		value, err := strconv.Atoi(offsetParam)
+		if err != nil || value < 0 {
+			return 0, 0, errors.New("invalid offset")
+		}
+		offset = value
+	}
+
+	return limit, offset, nil
+}
+
+func EncodeCursor(createdAt, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt + "|" + id))
+}
+
+func DecodeCursor(cursor string) (string, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", errors.New("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("invalid cursor")
+	}
+
+	return parts[0], parts[1], nil
+}
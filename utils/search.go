@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// BuildTSQuery converts a user search string into to_tsquery syntax.
+// Supported forms: plain terms (all must match), "quoted phrases",
+// -excluded terms and prefix* terms.
+func BuildTSQuery(input string) (string, error) {
+	var clauses []string
+
+	for _, token := range splitSearchTokens(input) {
+		negate := false
+		if strings.HasPrefix(token, "-") {
+			negate = true
+			token = token[1:]
+		}
+
+		var clause string
+		if strings.HasPrefix(token, `"`) {
+			words := searchLexemes(strings.Trim(token, `"`))
+			if len(words) == 0 {
+				continue
+			}
+			clause = strings.Join(words, " <-> ")
+			if len(words) > 1 {
+				clause = "(" + clause + ")"
+			}
+		} else {
+			prefix := strings.HasSuffix(token, "*")
+			words := searchLexemes(strings.TrimRight(token, "*"))
+			if len(words) == 0 {
+				continue
+			}
+			clause = strings.Join(words, " & ")
+			if prefix {
+				clause = strings.Join(words, ":* & ") + ":*"
+			}
+			if len(words) > 1 {
+				clause = "(" + clause + ")"
+			}
+		}
+
+		if negate {
+			clause = "!" + clause
+		}
+		clauses = append(clauses, clause)
+	}
+
+	if len(clauses) == 0 {
+		return "", errors.New("invalid search query")
+	}
+
+	return strings.Join(clauses, " & "), nil
+}
+
+func splitSearchTokens(input string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuote := false
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			current.WriteRune(r)
+			if inQuote {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// searchLexemes lowercases text and splits it on anything that is not a
+// letter, digit or underscore, so no tsquery operators can slip through.
+func searchLexemes(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
@@ -0,0 +1,48 @@
+package utils
+
+import "testing"
+
+func TestBuildTSQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "single term", input: "golang", want: "golang"},
+		{name: "terms are lowercased and all required", input: "Go Lang", want: "go & lang"},
+		{name: "quoted phrase keeps word order", input: `"hello world"`, want: "(hello <-> world)"},
+		{name: "single word phrase", input: `"hello"`, want: "hello"},
+		{name: "unterminated quote runs to the end", input: `"hello world`, want: "(hello <-> world)"},
+		{name: "excluded term", input: "go -spam", want: "go & !spam"},
+		{name: "excluded phrase", input: `-"buy now"`, want: "!(buy <-> now)"},
+		{name: "prefix term", input: "gola*", want: "gola:*"},
+		{name: "prefix applies to every word of a split term", input: "foo-bar*", want: "(foo:* & bar:*)"},
+		{name: "operator characters split words", input: "a&b|c", want: "(a & b & c)"},
+		{name: "operators are stripped from terms", input: "!(x) <-> y:*", want: "x & y:*"},
+		{name: "multi-byte terms", input: "Kopi Susu 日本", want: "kopi & susu & 日本"},
+		{name: "empty input", input: "", wantErr: true},
+		{name: "only whitespace", input: "   ", wantErr: true},
+		{name: "only operators", input: "&& | !", wantErr: true},
+		{name: "empty phrase", input: `""`, wantErr: true},
+		{name: "bare minus", input: "-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildTSQuery(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BuildTSQuery(%q) = %q, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildTSQuery(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildTSQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
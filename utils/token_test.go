@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyToken(t *testing.T) {
+	const secret = "s3cret"
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	valid := SignToken(secret, "user-1", now.Add(time.Hour))
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		want    string
+		wantErr string
+	}{
+		{name: "valid token", secret: secret, token: valid, want: "user-1"},
+		{name: "expired token", secret: secret, token: SignToken(secret, "user-1", now.Add(-time.Minute)), wantErr: "token expired"},
+		{name: "token expiring now", secret: secret, token: SignToken(secret, "user-1", now), wantErr: "token expired"},
+		{name: "tampered user ID", secret: secret, token: "user-2." + parts[1] + "." + parts[2], wantErr: "invalid token"},
+		{name: "tampered expiry", secret: secret, token: parts[0] + ".9999999999." + parts[2], wantErr: "invalid token"},
+		{name: "tampered signature", secret: secret, token: parts[0] + "." + parts[1] + ".AAAA", wantErr: "invalid token"},
+		{name: "signed with another secret", secret: secret, token: SignToken("other", "user-1", now.Add(time.Hour)), wantErr: "invalid token"},
+		{name: "non-numeric expiry", secret: secret, token: "user-1.soon." + tokenSignature(secret, "user-1.soon"), wantErr: "invalid token"},
+		{name: "empty user ID", secret: secret, token: SignToken(secret, "", now.Add(time.Hour)), wantErr: "invalid token"},
+		{name: "too few parts", secret: secret, token: "user-1", wantErr: "invalid token"},
+		{name: "too many parts", secret: secret, token: valid + ".extra", wantErr: "invalid token"},
+		{name: "empty token", secret: secret, token: "", wantErr: "invalid token"},
+		{name: "no secret configured", secret: "", token: valid, wantErr: "token authentication is not configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyToken(tt.secret, tt.token, now)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("VerifyToken() = %q, %v, want error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyToken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}